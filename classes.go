@@ -0,0 +1,271 @@
+package bel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// classRenderer produces classes with fromJSON/toJSON helpers for structs
+type classRenderer struct {
+	// classes contains the types which we render as class
+	classes map[string]bool
+	// unions contains the named unions, e.g. sum types whose variants are classes
+	unions map[string]TypescriptType
+	// discriminators contains the value of the discriminator member of each class by member name
+	discriminators map[string]map[string]string
+}
+
+func newClassRenderer(types []TypescriptType) *classRenderer {
+	var (
+		classes        = make(map[string]bool)
+		unions         = make(map[string]TypescriptType)
+		discriminators = make(map[string]map[string]string)
+	)
+	for _, t := range types {
+		if t.Kind == TypescriptUnionKind {
+			unions[t.Name] = t
+		}
+		if !isClass(t) {
+			continue
+		}
+		classes[t.Name] = true
+		for _, m := range t.Members {
+			if m.Type.Kind != TypescriptLiteralKind {
+				continue
+			}
+			if discriminators[t.Name] == nil {
+				discriminators[t.Name] = make(map[string]string)
+			}
+			discriminators[t.Name][m.Name] = m.Type.Name
+		}
+	}
+	return &classRenderer{classes: classes, unions: unions, discriminators: discriminators}
+}
+
+// isClass returns true if t is a struct which we can render as a class
+func isClass(t TypescriptType) bool {
+	if t.Kind != TypescriptInterfaceKind {
+		return false
+	}
+	for _, m := range t.Members {
+		if m.IsFunction {
+			return false
+		}
+	}
+	return true
+}
+
+// Class produces an exported class for a struct. Its static fromJSON method constructs the class
+// (and all classes it contains) from parsed JSON and converts mapped types, e.g. RFC3339 strings
+// to Date. toJSON inverts this.
+func (c *classRenderer) Class(t TypescriptType) string {
+	var res strings.Builder
+	res.WriteString(docComment(t.Comment, 0))
+	fmt.Fprintf(&res, "export class %s {\n", t.Name)
+	for _, m := range t.Members {
+		res.WriteString(docComment(m.Comment, 1))
+		mod := "!"
+		if m.IsOptional {
+			mod = "?"
+		}
+		fmt.Fprintf(&res, "    %s%s: %s;\n", propertyName(m.Name), mod, typeExpr(classFieldType(m.Type)))
+	}
+
+	fmt.Fprintf(&res, "\n    static fromJSON(obj: any): %s {\n", t.Name)
+	fmt.Fprintf(&res, "        const res = new %s();\n", t.Name)
+	for _, m := range t.Members {
+		src := fmt.Sprintf("obj[%s]", strconv.Quote(m.Name))
+		fmt.Fprintf(&res, "        res[%s] = %s;\n", strconv.Quote(m.Name), c.convertMember(src, m, 0, c.decode))
+	}
+	res.WriteString("        return res;\n    }\n")
+
+	res.WriteString("\n    toJSON(): any {\n        return {\n")
+	for _, m := range t.Members {
+		src := fmt.Sprintf("this[%s]", strconv.Quote(m.Name))
+		fmt.Fprintf(&res, "            %s: %s,\n", strconv.Quote(m.Name), c.convertMember(src, m, 0, c.encode))
+	}
+	res.WriteString("        };\n    }\n}\n")
+
+	return res.String()
+}
+
+// classFieldType maps a type to the type it has as class field, e.g. a date-time string becomes a Date
+func classFieldType(t TypescriptType) TypescriptType {
+	if t.Kind == TypescriptSimpleKind && t.Format == "date-time" {
+		t.Name = "Date"
+		return t
+	}
+	if n := quotedNumberType(t); n != "" {
+		t.Name = n
+		return t
+	}
+	if t.Kind == TypescriptArrayKind || t.Kind == TypescriptMapKind {
+		// nil slices and maps are null in JSON, and fromJSON keeps them that way
+		t.IsNullable = true
+	}
+
+	if len(t.Params) > 0 {
+		params := make([]TypescriptType, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = classFieldType(p)
+		}
+		t.Params = params
+	}
+	if len(t.Members) > 0 {
+		members := make([]TypescriptMember, len(t.Members))
+		for i, m := range t.Members {
+			m.Type = classFieldType(m.Type)
+			members[i] = m
+		}
+		t.Members = members
+	}
+	return t
+}
+
+// quotedNumberType returns the class field type of a number encoded as JSON string (using the `,string` option),
+// or "" if t is not such a number. 64 bit integers become bigint as they exceed the precision of number.
+func quotedNumberType(t TypescriptType) string {
+	if t.Kind != TypescriptSimpleKind || t.Name != "string" {
+		return ""
+	}
+	switch t.Format {
+	case "int64", "uint64":
+		return "bigint"
+	case "int32", "float", "double":
+		return "number"
+	}
+	return ""
+}
+
+// converter converts the value of expr of type t. depth is used to produce unique variable names.
+type converter func(expr string, t TypescriptType, depth int) string
+
+func (c *classRenderer) convertMember(expr string, m TypescriptMember, depth int, conv converter) string {
+	res := c.convert(expr, m.Type, depth, conv)
+	if m.IsOptional && res != expr {
+		res = fmt.Sprintf("%s === undefined ? undefined : %s", expr, res)
+	}
+	return res
+}
+
+// convert applies a converter to expr and handles null values. Besides pointers, nil slices and maps are null in JSON.
+func (c *classRenderer) convert(expr string, t TypescriptType, depth int, conv converter) string {
+	res := conv(expr, t, depth)
+	if (t.IsNullable || t.Kind == TypescriptArrayKind || t.Kind == TypescriptMapKind) && res != expr {
+		res = fmt.Sprintf("%s === null ? null : %s", expr, res)
+	}
+	return res
+}
+
+// decode converts parsed JSON to its class field representation
+func (c *classRenderer) decode(expr string, t TypescriptType, depth int) string {
+	switch {
+	case t.Kind == TypescriptSimpleKind && t.Format == "date-time":
+		return fmt.Sprintf("new Date(%s)", expr)
+	case quotedNumberType(t) == "bigint":
+		return fmt.Sprintf("BigInt(%s)", expr)
+	case quotedNumberType(t) == "number":
+		return fmt.Sprintf("Number(%s)", expr)
+	case t.Kind == TypescriptSimpleKind && c.classes[t.Name]:
+		return fmt.Sprintf("%s.fromJSON(%s)", t.Name, expr)
+	case t.Kind == TypescriptSimpleKind && c.unions[t.Name].Discriminator != "":
+		return c.decodeUnion(expr, c.unions[t.Name])
+	case t.Kind == TypescriptUnionKind && t.Discriminator != "":
+		return c.decodeUnion(expr, t)
+	}
+	return c.convertContainer(expr, t, depth, c.decode)
+}
+
+// decodeUnion constructs the class of a union's variant, which it finds using the discriminator member
+func (c *classRenderer) decodeUnion(expr string, t TypescriptType) string {
+	res := expr
+	for i := len(t.Params) - 1; i >= 0; i-- {
+		p := t.Params[i]
+		value, ok := c.discriminators[p.Name][t.Discriminator]
+		if p.Kind != TypescriptSimpleKind || !ok {
+			continue
+		}
+		res = fmt.Sprintf("%s[%s] === %s ? %s.fromJSON(%s) : %s", expr, strconv.Quote(t.Discriminator), value, p.Name, expr, res)
+	}
+	if res == expr {
+		return expr
+	}
+	return "(" + res + ")"
+}
+
+// encodeUnion converts the class of a union's variant to JSON
+func (c *classRenderer) encodeUnion(expr string, t TypescriptType) string {
+	res := expr
+	for i := len(t.Params) - 1; i >= 0; i-- {
+		p := t.Params[i]
+		if p.Kind != TypescriptSimpleKind || !c.classes[p.Name] {
+			continue
+		}
+		res = fmt.Sprintf("%s instanceof %s ? %s.toJSON() : %s", expr, p.Name, expr, res)
+	}
+	if res == expr {
+		return expr
+	}
+	return "(" + res + ")"
+}
+
+// encode converts a class field to its JSON representation
+func (c *classRenderer) encode(expr string, t TypescriptType, depth int) string {
+	switch {
+	case t.Kind == TypescriptSimpleKind && t.Format == "date-time":
+		return fmt.Sprintf("%s.toISOString()", expr)
+	case quotedNumberType(t) != "":
+		return fmt.Sprintf("String(%s)", expr)
+	case t.Kind == TypescriptSimpleKind && c.classes[t.Name]:
+		return fmt.Sprintf("%s.toJSON()", expr)
+	case t.Kind == TypescriptSimpleKind && c.unions[t.Name].Kind == TypescriptUnionKind:
+		return c.encodeUnion(expr, c.unions[t.Name])
+	case t.Kind == TypescriptUnionKind:
+		return c.encodeUnion(expr, t)
+	}
+	return c.convertContainer(expr, t, depth, c.encode)
+}
+
+// convertContainer converts the elements of arrays, maps and anonymous structs.
+// If there's nothing to convert, expr is returned as is.
+func (c *classRenderer) convertContainer(expr string, t TypescriptType, depth int, conv converter) string {
+	elem := fmt.Sprintf("e%d", depth)
+	switch t.Kind {
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return expr
+		}
+		ec := c.convert(elem, t.Params[0], depth+1, conv)
+		if ec == elem {
+			return expr
+		}
+		return fmt.Sprintf("(%s as any[]).map((%s: any) => %s)", expr, elem, ec)
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return expr
+		}
+		ec := c.convert(elem, t.Params[1], depth+1, conv)
+		if ec == elem {
+			return expr
+		}
+		key := fmt.Sprintf("k%d", depth)
+		return fmt.Sprintf("Object.fromEntries(Object.entries(%s).map(([%s, %s]: [string, any]) => [%s, %s]))", expr, key, elem, key, ec)
+	case TypescriptInterfaceKind:
+		var (
+			fields  []string
+			changed bool
+		)
+		for _, m := range t.Members {
+			src := fmt.Sprintf("%s[%s]", expr, strconv.Quote(m.Name))
+			mc := c.convertMember(src, m, depth+1, conv)
+			changed = changed || mc != src
+			fields = append(fields, fmt.Sprintf("%s: %s", strconv.Quote(m.Name), mc))
+		}
+		if !changed {
+			return expr
+		}
+		return "{ " + strings.Join(fields, ", ") + " }"
+	}
+	return expr
+}
@@ -0,0 +1,163 @@
+package bel
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Event happened at some point in time
+type Event struct {
+	At       time.Time              `json:"at"`
+	Until    *time.Time             `json:"until,omitempty"`
+	Source   AnotherTestStruct      `json:"source"`
+	Related  []*AnotherTestStruct   `json:"related"`
+	Metadata map[string]string      `json:"metadata"`
+	History  map[string][]time.Time `json:"history"`
+}
+
+func TestRenderClass(t *testing.T) {
+	extract, err := Extract(Event{}, FollowStructs, TimeAsString, SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	classes := newClassRenderer(extract)
+	expectation := map[string]string{
+		"AnotherTestStruct": `export class AnotherTestStruct {
+    Bar!: boolean;
+    Foo!: string;
+
+    static fromJSON(obj: any): AnotherTestStruct {
+        const res = new AnotherTestStruct();
+        res["Bar"] = obj["Bar"];
+        res["Foo"] = obj["Foo"];
+        return res;
+    }
+
+    toJSON(): any {
+        return {
+            "Bar": this["Bar"],
+            "Foo": this["Foo"],
+        };
+    }
+}
+`,
+		"Event": `export class Event {
+    at!: Date;
+    history!: { [key: string]: Date[] | null } | null;
+    metadata!: { [key: string]: string } | null;
+    related!: (AnotherTestStruct | null)[] | null;
+    source!: AnotherTestStruct;
+    until?: Date | null;
+
+    static fromJSON(obj: any): Event {
+        const res = new Event();
+        res["at"] = new Date(obj["at"]);
+        res["history"] = obj["history"] === null ? null : Object.fromEntries(Object.entries(obj["history"]).map(([k0, e0]: [string, any]) => [k0, e0 === null ? null : (e0 as any[]).map((e1: any) => new Date(e1))]));
+        res["metadata"] = obj["metadata"];
+        res["related"] = obj["related"] === null ? null : (obj["related"] as any[]).map((e0: any) => e0 === null ? null : AnotherTestStruct.fromJSON(e0));
+        res["source"] = AnotherTestStruct.fromJSON(obj["source"]);
+        res["until"] = obj["until"] === undefined ? undefined : obj["until"] === null ? null : new Date(obj["until"]);
+        return res;
+    }
+
+    toJSON(): any {
+        return {
+            "at": this["at"].toISOString(),
+            "history": this["history"] === null ? null : Object.fromEntries(Object.entries(this["history"]).map(([k0, e0]: [string, any]) => [k0, e0 === null ? null : (e0 as any[]).map((e1: any) => e1.toISOString())])),
+            "metadata": this["metadata"],
+            "related": this["related"] === null ? null : (this["related"] as any[]).map((e0: any) => e0 === null ? null : e0.toJSON()),
+            "source": this["source"].toJSON(),
+            "until": this["until"] === undefined ? undefined : this["until"] === null ? null : this["until"].toISOString(),
+        };
+    }
+}
+`,
+	}
+	for _, tpe := range extract {
+		if act := classes.Class(tpe); act != expectation[tpe.Name] {
+			t.Errorf("unexpected class for %s:\n%s", tpe.Name, act)
+		}
+	}
+}
+
+// Measurement is encoded with numbers as strings, and may have nil slices and maps
+type Measurement struct {
+	Count   int64             `json:"count,string"`
+	Ratio   float64           `json:"ratio,string"`
+	Valid   bool              `json:"valid,string"`
+	Samples []time.Time       `json:"samples"`
+	Labels  map[string]string `json:"labels"`
+	Limits  map[string]int64  `json:"limits"`
+}
+
+func TestRenderClassFromJSON(t *testing.T) {
+	extract, err := Extract(Measurement{}, TimeAsString)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(extract) != 1 {
+		t.Errorf("unexpected extract: %v", extract)
+		return
+	}
+
+	expectation := `export class Measurement {
+    count!: bigint;
+    ratio!: number;
+    valid!: string;
+    samples!: Date[] | null;
+    labels!: { [key: string]: string } | null;
+    limits!: { [key: string]: number } | null;
+
+    static fromJSON(obj: any): Measurement {
+        const res = new Measurement();
+        res["count"] = BigInt(obj["count"]);
+        res["ratio"] = Number(obj["ratio"]);
+        res["valid"] = obj["valid"];
+        res["samples"] = obj["samples"] === null ? null : (obj["samples"] as any[]).map((e0: any) => new Date(e0));
+        res["labels"] = obj["labels"];
+        res["limits"] = obj["limits"];
+        return res;
+    }
+
+    toJSON(): any {
+        return {
+            "count": String(this["count"]),
+            "ratio": String(this["ratio"]),
+            "valid": this["valid"],
+            "samples": this["samples"] === null ? null : (this["samples"] as any[]).map((e0: any) => e0.toISOString()),
+            "labels": this["labels"],
+            "limits": this["limits"],
+        };
+    }
+}
+`
+	if act := newClassRenderer(extract).Class(extract[0]); act != expectation {
+		t.Errorf("unexpected class:\n%s", act)
+	}
+}
+
+func TestRenderClassComments(t *testing.T) {
+	tpe := TypescriptType{Name: "Note", Kind: TypescriptInterfaceKind, Comment: "Note is documented\n\nacross several lines", Members: []TypescriptMember{
+		{TypedElement: TypedElement{Name: "text", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}, Comment: "text is the content\nof the note"},
+	}}
+
+	expectation := `/**
+ * Note is documented
+ *
+ * across several lines
+ */
+export class Note {
+    /**
+     * text is the content
+     * of the note
+     */
+    text!: string;
+`
+	if act := newClassRenderer([]TypescriptType{tpe}).Class(tpe); !strings.HasPrefix(act, expectation) {
+		t.Errorf("unexpected class:\n%s", act)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/32leaves/bel"
+)
+
+// runDiff compares two schemas written using bel.WriteSchema. It exits with 1 if there are breaking changes.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	all := flags.Bool("all", false, "report non-breaking changes as well")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bel diff [-all] <old-schema.json> <new-schema.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	prev, err := readSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	next, err := readSchema(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	var breaking int
+	for _, c := range bel.Diff(prev, next) {
+		if c.IsBreaking() {
+			breaking++
+			fmt.Fprintf(stdout, "BREAKING %s\n", c)
+		} else if *all {
+			fmt.Fprintf(stdout, "         %s\n", c)
+		}
+	}
+	if breaking > 0 {
+		fmt.Fprintf(stderr, "found %d breaking changes\n", breaking)
+		return 1
+	}
+	return 0
+}
+
+func readSchema(fn string) ([]bel.TypescriptType, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	res, err := bel.ReadSchema(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read schema %s: %v", fn, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bel-diff")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	schemas := map[string]string{
+		"prev.json": `{"version": 1, "types": [{"name": "Foo", "kind": "iface", "members": [{"name": "bar", "type": {"name": "string", "kind": "simple"}}]}]}`,
+		"next.json": `{"version": 1, "types": [{"name": "Foo", "kind": "iface", "members": [{"name": "bar", "type": {"name": "string", "kind": "simple"}}, {"name": "baz", "optional": true, "type": {"name": "number", "kind": "simple"}}]}]}`,
+		"gone.json": `{"version": 1, "types": []}`,
+	}
+	for fn, content := range schemas {
+		err = ioutil.WriteFile(filepath.Join(dir, fn), []byte(content), 0644)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		Args     []string
+		ExitCode int
+		Stdout   string
+	}{
+		{"compatible", []string{"prev.json", "next.json"}, 0, ""},
+		{"compatible all", []string{"-all", "prev.json", "next.json"}, 0, "         Foo.baz: member-added (optional)\n"},
+		{"breaking", []string{"prev.json", "gone.json"}, 1, "BREAKING Foo: type-removed\n"},
+		{"missing file", []string{"prev.json", "does-not-exist.json"}, 2, ""},
+		{"missing args", []string{"prev.json"}, 2, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			args := []string{"diff"}
+			for _, a := range test.Args {
+				if filepath.Ext(a) == ".json" {
+					a = filepath.Join(dir, a)
+				}
+				args = append(args, a)
+			}
+
+			var stdout, stderr bytes.Buffer
+			if code := run(args, &stdout, &stderr); code != test.ExitCode {
+				t.Errorf("unexpected exit code %d: %s", code, stderr.String())
+			}
+			if stdout.String() != test.Stdout {
+				t.Errorf("unexpected output: %q", stdout.String())
+			}
+		})
+	}
+}
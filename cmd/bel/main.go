@@ -0,0 +1,53 @@
+// Command bel works with the APIs extracted by github.com/32leaves/bel
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of bel. It returns the exit code.
+type command struct {
+	Description string
+	Run         func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"diff":     {"compare two API schemas and report breaking changes", runDiff},
+	"registry": {"generate code which registers the enums and docs of a package", runRegistry},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return cmd.Run(args[1:], stdout, stderr)
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "usage: bel <command> [arguments]")
+	fmt.Fprintln(out, "\ncommands:")
+
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(out, "  %-10s %s\n", n, commands[n].Description)
+	}
+}
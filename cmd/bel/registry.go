@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/32leaves/bel"
+)
+
+// runRegistry writes a Go file which registers the enums and documentation of a package with bel.DefaultRegistry
+func runRegistry(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("registry", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "bel_registry.go", "output file, relative to the package directory. Use - for stdout")
+	module := flags.String("module", "", "import path of the package, if it cannot be determined from its go.mod")
+	minMembers := flags.Int("min-members", 1, "number of typed constants a type needs to be considered an enum")
+	tags := flags.String("tags", "", "comma-separated build tags to use when scanning the package")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bel registry [flags] <package-dir>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	dir := flags.Arg(0)
+
+	opts := []bel.ParsedSourceEnumOption{bel.MinEnumMembers(*minMembers)}
+	if *module != "" {
+		opts = append(opts, bel.ModulePath(*module))
+	}
+	if *tags != "" {
+		opts = append(opts, bel.BuildConstraints(runtime.GOOS, runtime.GOARCH, strings.Split(*tags, ",")...))
+	}
+
+	var out bytes.Buffer
+	if err := bel.WriteRegistry(&out, dir, opts...); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if *output == "-" {
+		stdout.Write(out.Bytes())
+		return 0
+	}
+
+	fn := *output
+	if !filepath.IsAbs(fn) {
+		fn = filepath.Join(dir, fn)
+	}
+	if err := ioutil.WriteFile(fn, out.Bytes(), 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
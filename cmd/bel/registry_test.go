@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bel-registry")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"go.mod": "module example.com/api\n",
+		"api.go": "package api\n\n// Ticket is an issue\ntype Ticket struct{}\n",
+	}
+	for fn, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, fn), []byte(content), 0644)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	registration := `bel.DefaultRegistry.RegisterTypeDoc("example.com/api", "Ticket", "Ticket is an issue")`
+	tests := []struct {
+		Name     string
+		Args     []string
+		ExitCode int
+		Stdout   string
+		File     string
+	}{
+		{"file", []string{dir}, 0, "", "bel_registry.go"},
+		{"custom file", []string{"-o", "zz_registry.go", dir}, 0, "", "zz_registry.go"},
+		{"stdout", []string{"-o", "-", dir}, 0, registration, ""},
+		{"module", []string{"-o", "-", "-module", "example.com/other", dir}, 0, `RegisterTypeDoc("example.com/other", "Ticket"`, ""},
+		{"missing dir", []string{filepath.Join(dir, "does-not-exist")}, 1, "", ""},
+		{"missing args", nil, 2, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"registry"}, test.Args...), &stdout, &stderr); code != test.ExitCode {
+				t.Errorf("unexpected exit code %d: %s", code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), test.Stdout) || (test.Stdout == "" && stdout.Len() > 0) {
+				t.Errorf("unexpected output: %q", stdout.String())
+			}
+			if test.File == "" {
+				return
+			}
+
+			fc, err := ioutil.ReadFile(filepath.Join(dir, test.File))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !strings.Contains(string(fc), registration) {
+				t.Errorf("unexpected registry:\n%s", fc)
+			}
+		})
+	}
+}
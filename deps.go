@@ -0,0 +1,83 @@
+package bel
+
+// dependencyOrder sorts types such that every type comes after the types it refers to.
+// Where that's impossible because types refer to each other, the types involved in the
+// reference cycle are reported as recursive. Apart from that the original order is kept.
+func dependencyOrder(types []TypescriptType) (ordered []TypescriptType, recursive map[string]bool) {
+	idx := make(map[string]int, len(types))
+	for i, t := range types {
+		idx[t.Name] = i
+	}
+	deps := make([][]int, len(types))
+	for i, t := range types {
+		walkReferences(t, func(name string) {
+			if j, ok := idx[name]; ok {
+				deps[i] = append(deps[i], j)
+			}
+		})
+	}
+
+	// Tarjan's algorithm emits strongly connected components in reverse topological order,
+	// i.e. dependencies first.
+	var (
+		index   = 0
+		indices = make([]int, len(types))
+		lowlink = make([]int, len(types))
+		onStack = make([]bool, len(types))
+		stack   []int
+		visit   func(v int)
+	)
+	for i := range indices {
+		indices[i] = -1
+	}
+	recursive = make(map[string]bool)
+	visit = func(v int) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		selfref := false
+		for _, w := range deps[v] {
+			if w == v {
+				selfref = true
+			}
+			if indices[w] < 0 {
+				visit(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && indices[w] < lowlink[v] {
+				lowlink[v] = indices[w]
+			}
+		}
+		if lowlink[v] != indices[v] {
+			return
+		}
+
+		var scc []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			scc = append(scc, w)
+			if w == v {
+				break
+			}
+		}
+		for i := len(scc) - 1; i >= 0; i-- {
+			t := types[scc[i]]
+			if len(scc) > 1 || selfref {
+				recursive[t.Name] = true
+			}
+			ordered = append(ordered, t)
+		}
+	}
+	for i := range types {
+		if indices[i] < 0 {
+			visit(i)
+		}
+	}
+	return ordered, recursive
+}
@@ -0,0 +1,311 @@
+package bel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies a difference between two versions of an API
+type ChangeKind string
+
+const (
+	// TypeRemoved means a type no longer exists
+	TypeRemoved ChangeKind = "type-removed"
+	// TypeAdded means a type was added
+	TypeAdded ChangeKind = "type-added"
+	// TypeChanged means a type changed its kind or definition, e.g. from interface to enum
+	TypeChanged ChangeKind = "type-changed"
+	// MemberRemoved means a member of an interface no longer exists
+	MemberRemoved ChangeKind = "member-removed"
+	// MemberRenamed means a member of an interface was replaced by a member with a different name but the same type
+	MemberRenamed ChangeKind = "member-renamed"
+	// MemberAdded means a member was added to an interface
+	MemberAdded ChangeKind = "member-added"
+	// MemberBecameRequired means an optional member is required now
+	MemberBecameRequired ChangeKind = "member-became-required"
+	// MemberBecameOptional means a required member is optional now
+	MemberBecameOptional ChangeKind = "member-became-optional"
+	// MemberTypeChanged means the type of a member changed
+	MemberTypeChanged ChangeKind = "member-type-changed"
+	// MethodSignatureChanged means the arguments or return type of a method changed
+	MethodSignatureChanged ChangeKind = "method-signature-changed"
+	// EnumValueRemoved means a member of an enum no longer exists
+	EnumValueRemoved ChangeKind = "enum-value-removed"
+	// EnumValueChanged means a member of an enum has a different value
+	EnumValueChanged ChangeKind = "enum-value-changed"
+	// EnumValueAdded means a member was added to an enum
+	EnumValueAdded ChangeKind = "enum-value-added"
+	// VariantRemoved means a variant of a union no longer exists
+	VariantRemoved ChangeKind = "variant-removed"
+	// VariantAdded means a variant was added to a union
+	VariantAdded ChangeKind = "variant-added"
+)
+
+// breakingChanges are the kinds of changes which break clients of an API
+var breakingChanges = map[ChangeKind]bool{
+	TypeRemoved:            true,
+	TypeChanged:            true,
+	MemberRemoved:          true,
+	MemberRenamed:          true,
+	MemberBecameRequired:   true,
+	MemberTypeChanged:      true,
+	MethodSignatureChanged: true,
+	EnumValueRemoved:       true,
+	EnumValueChanged:       true,
+	VariantRemoved:         true,
+}
+
+// Change is a difference between two versions of an API
+type Change struct {
+	Kind ChangeKind
+	// Path names the changed type, member or enum value, e.g. Foo.bar
+	Path string
+	// Old and New describe the changed element before and after the change, if applicable
+	Old string
+	New string
+}
+
+// IsBreaking returns true if the change breaks clients of the API
+func (c Change) IsBreaking() bool {
+	return breakingChanges[c.Kind]
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old != "" && c.New != "":
+		return fmt.Sprintf("%s: %s (%s -> %s)", c.Path, c.Kind, c.Old, c.New)
+	case c.Old != "":
+		return fmt.Sprintf("%s: %s (was %s)", c.Path, c.Kind, c.Old)
+	case c.New != "":
+		return fmt.Sprintf("%s: %s (%s)", c.Path, c.Kind, c.New)
+	}
+	return fmt.Sprintf("%s: %s", c.Path, c.Kind)
+}
+
+// Diff compares two versions of an API, e.g. a schema loaded using ReadSchema (prev) and the current extraction (next).
+// The changes are sorted by path.
+func Diff(prev, next []TypescriptType) []Change {
+	var (
+		res  []Change
+		olds = make(map[string]TypescriptType, len(prev))
+		news = make(map[string]TypescriptType, len(next))
+	)
+	for _, t := range prev {
+		olds[t.Name] = t
+	}
+	for _, t := range next {
+		news[t.Name] = t
+	}
+
+	for _, name := range sortedTypeNames(olds) {
+		o := olds[name]
+		n, ok := news[name]
+		if !ok {
+			res = append(res, Change{Kind: TypeRemoved, Path: name})
+			continue
+		}
+		res = append(res, diffType(name, o, n)...)
+	}
+	for _, name := range sortedTypeNames(news) {
+		if _, ok := olds[name]; !ok {
+			res = append(res, Change{Kind: TypeAdded, Path: name})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+	return res
+}
+
+func sortedTypeNames(types map[string]TypescriptType) []string {
+	res := make([]string, 0, len(types))
+	for n := range types {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func diffType(path string, prev, next TypescriptType) []Change {
+	if prev.Kind != next.Kind {
+		return []Change{{Kind: TypeChanged, Path: path, Old: string(prev.Kind), New: string(next.Kind)}}
+	}
+
+	switch prev.Kind {
+	case TypescriptInterfaceKind:
+		return diffMembers(path, prev.Members, next.Members)
+	case TypescriptEnumKind:
+		return diffEnumMembers(path, prev.EnumMembers, next.EnumMembers)
+	case TypescriptUnionKind:
+		if prev.Discriminator != next.Discriminator {
+			return []Change{{Kind: TypeChanged, Path: path, Old: prev.Discriminator, New: next.Discriminator}}
+		}
+		return diffVariants(path, prev.Params, next.Params)
+	}
+	if o, n := typeExpr(prev), typeExpr(next); o != n {
+		return []Change{{Kind: TypeChanged, Path: path, Old: o, New: n}}
+	}
+	return nil
+}
+
+func diffMembers(path string, prev, next []TypescriptMember) []Change {
+	var (
+		res     []Change
+		news    = make(map[string]TypescriptMember, len(next))
+		olds    = make(map[string]bool, len(prev))
+		removed []TypescriptMember
+		added   []TypescriptMember
+	)
+	for _, m := range next {
+		news[m.Name] = m
+	}
+	for _, o := range prev {
+		olds[o.Name] = true
+		n, ok := news[o.Name]
+		if !ok {
+			removed = append(removed, o)
+			continue
+		}
+		res = append(res, diffMember(path+"."+o.Name, o, n)...)
+	}
+	for _, n := range next {
+		if !olds[n.Name] {
+			added = append(added, n)
+		}
+	}
+
+	// a member which was removed is considered renamed if exactly one added member has the same signature, and vice versa
+	signatures := func(ms []TypescriptMember) map[string][]TypescriptMember {
+		res := make(map[string][]TypescriptMember)
+		for _, m := range ms {
+			sig := memberSignature(m)
+			res[sig] = append(res[sig], m)
+		}
+		return res
+	}
+	removedSigs, addedSigs := signatures(removed), signatures(added)
+	renamed := make(map[string]bool)
+	for _, o := range removed {
+		sig := memberSignature(o)
+		if len(removedSigs[sig]) != 1 || len(addedSigs[sig]) != 1 {
+			res = append(res, Change{Kind: MemberRemoved, Path: path + "." + o.Name})
+			continue
+		}
+		n := addedSigs[sig][0]
+		renamed[n.Name] = true
+		res = append(res, Change{Kind: MemberRenamed, Path: path + "." + o.Name, Old: o.Name, New: n.Name})
+	}
+	for _, n := range added {
+		if renamed[n.Name] {
+			continue
+		}
+		desc := "required"
+		if n.IsOptional {
+			desc = "optional"
+		}
+		res = append(res, Change{Kind: MemberAdded, Path: path + "." + n.Name, New: desc})
+	}
+	return res
+}
+
+func diffMember(path string, prev, next TypescriptMember) []Change {
+	var res []Change
+	if prev.IsOptional && !next.IsOptional {
+		res = append(res, Change{Kind: MemberBecameRequired, Path: path})
+	} else if !prev.IsOptional && next.IsOptional {
+		res = append(res, Change{Kind: MemberBecameOptional, Path: path})
+	}
+
+	switch {
+	case prev.IsFunction && next.IsFunction:
+		if o, n := methodSignature(prev), methodSignature(next); o != n {
+			res = append(res, Change{Kind: MethodSignatureChanged, Path: path, Old: o, New: n})
+		}
+	case prev.IsFunction != next.IsFunction:
+		res = append(res, Change{Kind: MemberTypeChanged, Path: path, Old: memberSignature(prev), New: memberSignature(next)})
+	case isAnonymousInterface(prev.Type) && isAnonymousInterface(next.Type) && prev.Type.IsNullable == next.Type.IsNullable:
+		res = append(res, diffMembers(path, prev.Type.Members, next.Type.Members)...)
+	default:
+		if o, n := typeExpr(prev.Type), typeExpr(next.Type); o != n {
+			res = append(res, Change{Kind: MemberTypeChanged, Path: path, Old: o, New: n})
+		}
+	}
+	return res
+}
+
+func isAnonymousInterface(t TypescriptType) bool {
+	return t.Kind == TypescriptInterfaceKind && t.Name == ""
+}
+
+// memberSignature describes the type of a member or method, disregarding its name
+func memberSignature(m TypescriptMember) string {
+	opt := ""
+	if m.IsOptional {
+		opt = "?"
+	}
+	if m.IsFunction {
+		return opt + methodSignature(m)
+	}
+	return opt + typeExpr(m.Type)
+}
+
+func methodSignature(m TypescriptMember) string {
+	args := make([]string, len(m.Args))
+	for i, a := range m.Args {
+		args[i] = typeExpr(a.Type)
+	}
+	return fmt.Sprintf("(%s) => %s", strings.Join(args, ", "), typeExpr(m.Type))
+}
+
+func diffVariants(path string, prev, next []TypescriptType) []Change {
+	var (
+		res  []Change
+		olds = make(map[string]bool, len(prev))
+		news = make(map[string]bool, len(next))
+	)
+	for _, t := range prev {
+		olds[typeExpr(t)] = true
+	}
+	for _, t := range next {
+		news[typeExpr(t)] = true
+	}
+	for _, t := range prev {
+		if v := typeExpr(t); !news[v] {
+			res = append(res, Change{Kind: VariantRemoved, Path: path, Old: v})
+		}
+	}
+	for _, t := range next {
+		if v := typeExpr(t); !olds[v] {
+			res = append(res, Change{Kind: VariantAdded, Path: path, New: v})
+		}
+	}
+	return res
+}
+
+func diffEnumMembers(path string, prev, next []TypescriptEnumMember) []Change {
+	var (
+		res  []Change
+		news = make(map[string]TypescriptEnumMember, len(next))
+		olds = make(map[string]bool, len(prev))
+	)
+	for _, m := range next {
+		news[m.Name] = m
+	}
+	for _, o := range prev {
+		olds[o.Name] = true
+		n, ok := news[o.Name]
+		if !ok {
+			res = append(res, Change{Kind: EnumValueRemoved, Path: path + "." + o.Name, Old: o.Value})
+			continue
+		}
+		if o.Value != n.Value {
+			res = append(res, Change{Kind: EnumValueChanged, Path: path + "." + o.Name, Old: o.Value, New: n.Value})
+		}
+	}
+	for _, n := range next {
+		if !olds[n.Name] {
+			res = append(res, Change{Kind: EnumValueAdded, Path: path + "." + n.Name, New: n.Value})
+		}
+	}
+	return res
+}
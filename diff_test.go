@@ -0,0 +1,105 @@
+package bel
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDiff(t *testing.T) {
+	str := TypescriptType{Name: "string", Kind: TypescriptSimpleKind}
+	num := TypescriptType{Name: "number", Kind: TypescriptSimpleKind}
+	member := func(name string, tpe TypescriptType, optional bool) TypescriptMember {
+		return TypescriptMember{TypedElement: TypedElement{Name: name, Type: tpe}, IsOptional: optional}
+	}
+	method := func(name string, ret TypescriptType, args ...TypescriptType) TypescriptMember {
+		m := TypescriptMember{TypedElement: TypedElement{Name: name, Type: ret}, IsFunction: true}
+		for i, a := range args {
+			m.Args = append(m.Args, TypedElement{Name: string(rune('a' + i)), Type: a})
+		}
+		return m
+	}
+	ref := func(name string) TypescriptType {
+		return TypescriptType{Name: name, Kind: TypescriptSimpleKind}
+	}
+
+	prev := []TypescriptType{
+		{Name: "Removed", Kind: TypescriptInterfaceKind},
+		{Name: "Kind", Kind: TypescriptInterfaceKind},
+		{Name: "Struct", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+			member("removed", num, false),
+			member("oldName", str, false),
+			member("becomesRequired", str, true),
+			member("becomesOptional", str, false),
+			member("changesType", str, false),
+			member("unchanged", str, false),
+			member("nested", TypescriptType{Kind: TypescriptInterfaceKind, Members: []TypescriptMember{member("a", str, false)}}, false),
+		}},
+		{Name: "Service", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+			method("Changes", str, str),
+			method("Stays", str, str),
+		}},
+		{Name: "Enum", Kind: TypescriptEnumKind, EnumMembers: []TypescriptEnumMember{
+			{Name: "One", Value: "1"},
+			{Name: "Two", Value: "2"},
+			{Name: "Three", Value: "3"},
+		}},
+		{Name: "Union", Kind: TypescriptUnionKind, Discriminator: "kind", Params: []TypescriptType{ref("A"), ref("B")}},
+	}
+	next := []TypescriptType{
+		{Name: "Added", Kind: TypescriptInterfaceKind},
+		{Name: "Kind", Kind: TypescriptEnumKind},
+		{Name: "Struct", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+			member("newName", str, false),
+			member("becomesRequired", str, false),
+			member("becomesOptional", str, true),
+			member("changesType", num, false),
+			member("unchanged", str, false),
+			member("nested", TypescriptType{Kind: TypescriptInterfaceKind, Members: []TypescriptMember{member("a", num, false)}}, false),
+			member("added", num, true),
+		}},
+		{Name: "Service", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+			method("Changes", str, str, num),
+			method("Stays", str, str),
+		}},
+		{Name: "Enum", Kind: TypescriptEnumKind, EnumMembers: []TypescriptEnumMember{
+			{Name: "One", Value: "1"},
+			{Name: "Two", Value: "22"},
+			{Name: "Four", Value: "4"},
+		}},
+		{Name: "Union", Kind: TypescriptUnionKind, Discriminator: "kind", Params: []TypescriptType{ref("B"), ref("C")}},
+	}
+
+	expectation := []Change{
+		{Kind: TypeAdded, Path: "Added"},
+		{Kind: EnumValueAdded, Path: "Enum.Four", New: "4"},
+		{Kind: EnumValueRemoved, Path: "Enum.Three", Old: "3"},
+		{Kind: EnumValueChanged, Path: "Enum.Two", Old: "2", New: "22"},
+		{Kind: TypeChanged, Path: "Kind", Old: "iface", New: "enum"},
+		{Kind: TypeRemoved, Path: "Removed"},
+		{Kind: MethodSignatureChanged, Path: "Service.Changes", Old: "(string) => string", New: "(string, number) => string"},
+		{Kind: MemberAdded, Path: "Struct.added", New: "optional"},
+		{Kind: MemberBecameOptional, Path: "Struct.becomesOptional"},
+		{Kind: MemberBecameRequired, Path: "Struct.becomesRequired"},
+		{Kind: MemberTypeChanged, Path: "Struct.changesType", Old: "string", New: "number"},
+		{Kind: MemberTypeChanged, Path: "Struct.nested.a", Old: "string", New: "number"},
+		{Kind: MemberRenamed, Path: "Struct.oldName", Old: "oldName", New: "newName"},
+		{Kind: MemberRemoved, Path: "Struct.removed"},
+		{Kind: VariantRemoved, Path: "Union", Old: "A"},
+		{Kind: VariantAdded, Path: "Union", New: "C"},
+	}
+	if diff := deep.Equal(Diff(prev, next), expectation); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	extract, err := Extract(StructOfAllKind{}, FollowStructs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if changes := Diff(extract, extract); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
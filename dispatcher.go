@@ -0,0 +1,437 @@
+package bel
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dispatcherRuntime is the support code shared by all dispatchers in a generated file
+const dispatcherRuntime = `
+// JSON-RPC 2.0 error codes
+const (
+	JSONRPCParseError     = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams  = -32602
+	JSONRPCInternalError  = -32603
+	JSONRPCServerError    = -32000
+)
+
+// JSONRPCError is a JSON-RPC 2.0 error object. Methods can return a *JSONRPCError
+// to control the error response; all other errors become a JSONRPCServerError.
+type JSONRPCError struct {
+	Code    int         ` + "`json:\"code\"`" + `
+	Message string      ` + "`json:\"message\"`" + `
+	Data    interface{} ` + "`json:\"data,omitempty\"`" + `
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+type jsonrpcRequest struct {
+	JSONRPC string          ` + "`json:\"jsonrpc\"`" + `
+	ID      json.RawMessage ` + "`json:\"id,omitempty\"`" + `
+	Method  string          ` + "`json:\"method\"`" + `
+	Params  json.RawMessage ` + "`json:\"params,omitempty\"`" + `
+}
+
+type jsonrpcResponse struct {
+	JSONRPC string          ` + "`json:\"jsonrpc\"`" + `
+	ID      json.RawMessage ` + "`json:\"id\"`" + `
+	Result  json.RawMessage ` + "`json:\"result,omitempty\"`" + `
+	Error   *JSONRPCError   ` + "`json:\"error,omitempty\"`" + `
+}
+
+// jsonrpcMethod decodes the params of a call and invokes the method
+type jsonrpcMethod func(params json.RawMessage) (interface{}, error)
+
+// dispatchJSONRPC handles a single or batch request. It returns nil if there's nothing to respond, i.e. for notifications.
+func dispatchJSONRPC(methods map[string]jsonrpcMethod, req []byte) []byte {
+	req = bytes.TrimSpace(req)
+	if len(req) > 0 && req[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(req, &batch); err != nil {
+			return marshalJSONRPC(jsonrpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCParseError, Message: err.Error()}))
+		}
+		if len(batch) == 0 {
+			return marshalJSONRPC(jsonrpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: "empty batch"}))
+		}
+
+		var resps []*jsonrpcResponse
+		for _, r := range batch {
+			if resp := dispatchJSONRPCCall(methods, r); resp != nil {
+				resps = append(resps, resp)
+			}
+		}
+		if len(resps) == 0 {
+			return nil
+		}
+		return marshalJSONRPC(resps)
+	}
+
+	resp := dispatchJSONRPCCall(methods, req)
+	if resp == nil {
+		return nil
+	}
+	return marshalJSONRPC(resp)
+}
+
+func dispatchJSONRPCCall(methods map[string]jsonrpcMethod, raw []byte) *jsonrpcResponse {
+	var req jsonrpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return jsonrpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCParseError, Message: err.Error()})
+		}
+		return jsonrpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: err.Error()})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return jsonrpcErrorResponse(req.ID, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+	}
+
+	method, ok := methods[req.Method]
+	if !ok {
+		if req.ID == nil {
+			return nil
+		}
+		return jsonrpcErrorResponse(req.ID, &JSONRPCError{Code: JSONRPCMethodNotFound, Message: "method not found: " + req.Method})
+	}
+	res, err := method(req.Params)
+	if req.ID == nil {
+		// notifications get no response
+		return nil
+	}
+	if err != nil {
+		var rpcErr *JSONRPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &JSONRPCError{Code: JSONRPCServerError, Message: err.Error()}
+		}
+		return jsonrpcErrorResponse(req.ID, rpcErr)
+	}
+	result, err := json.Marshal(res)
+	if err != nil {
+		return jsonrpcErrorResponse(req.ID, &JSONRPCError{Code: JSONRPCInternalError, Message: err.Error()})
+	}
+	return &jsonrpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func jsonrpcErrorResponse(id json.RawMessage, err *JSONRPCError) *jsonrpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &jsonrpcResponse{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+func marshalJSONRPC(resp interface{}) []byte {
+	fc, err := json.Marshal(resp)
+	if err != nil {
+		fc, _ = json.Marshal(jsonrpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCInternalError, Message: err.Error()}))
+	}
+	return fc
+}
+
+// decodeJSONRPCParams decodes positional (array) or named (object) params into args
+func decodeJSONRPCParams(params json.RawMessage, names []string, args ...interface{}) error {
+	params = bytes.TrimSpace(params)
+	invalid := func(err error) error {
+		return &JSONRPCError{Code: JSONRPCInvalidParams, Message: err.Error()}
+	}
+
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("null")):
+		if len(args) > 0 {
+			return invalid(fmt.Errorf("expected %d params", len(args)))
+		}
+	case params[0] == '[':
+		var positional []json.RawMessage
+		if err := json.Unmarshal(params, &positional); err != nil {
+			return invalid(err)
+		}
+		if len(positional) != len(args) {
+			return invalid(fmt.Errorf("expected %d params, got %d", len(args), len(positional)))
+		}
+		for i, p := range positional {
+			if err := json.Unmarshal(p, args[i]); err != nil {
+				return invalid(fmt.Errorf("%s: %v", names[i], err))
+			}
+		}
+	case params[0] == '{':
+		var named map[string]json.RawMessage
+		if err := json.Unmarshal(params, &named); err != nil {
+			return invalid(err)
+		}
+		for i, n := range names {
+			p, ok := named[n]
+			if !ok {
+				return invalid(fmt.Errorf("missing param %s", n))
+			}
+			if err := json.Unmarshal(p, args[i]); err != nil {
+				return invalid(fmt.Errorf("%s: %v", n, err))
+			}
+		}
+	default:
+		return invalid(fmt.Errorf("params must be an array or object"))
+	}
+	return nil
+}
+`
+
+// goImports assigns package names to import paths for generated Go code
+type goImports struct {
+	self    string
+	aliases map[string]string
+	taken   map[string]bool
+}
+
+func newGoImports(self string, std ...string) *goImports {
+	res := &goImports{self: self, aliases: make(map[string]string), taken: make(map[string]bool)}
+	for _, p := range std {
+		res.alias(p)
+	}
+	return res
+}
+
+// alias returns the name under which the package is imported
+func (i *goImports) alias(pkgPath string) string {
+	if a, ok := i.aliases[pkgPath]; ok {
+		return a
+	}
+
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, path.Base(pkgPath))
+	alias := base
+	for n := 2; i.taken[alias]; n++ {
+		alias = fmt.Sprintf("%s%d", base, n)
+	}
+	i.aliases[pkgPath] = alias
+	i.taken[alias] = true
+	return alias
+}
+
+// typeExpr produces the Go type expression for t
+func (i *goImports) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" || t.PkgPath() == i.self {
+			return t.Name(), nil
+		}
+		return i.alias(t.PkgPath()) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		elem, err := i.typeExpr(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		switch t.Kind() {
+		case reflect.Ptr:
+			return "*" + elem, nil
+		case reflect.Slice:
+			return "[]" + elem, nil
+		default:
+			return fmt.Sprintf("[%d]%s", t.Len(), elem), nil
+		}
+	case reflect.Map:
+		key, err := i.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := i.typeExpr(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map[%s]%s", key, elem), nil
+	case reflect.Struct:
+		fields := make([]string, t.NumField())
+		for j := 0; j < t.NumField(); j++ {
+			f := t.Field(j)
+			ft, err := i.typeExpr(f.Type)
+			if err != nil {
+				return "", err
+			}
+			fields[j] = f.Name + " " + ft
+			if f.Anonymous {
+				fields[j] = ft
+			}
+			if f.Tag != "" {
+				fields[j] += " " + strconv.Quote(string(f.Tag))
+			}
+		}
+		return "struct{ " + strings.Join(fields, "; ") + " }", nil
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	}
+	return "", fmt.Errorf("cannot produce Go type expression for %v", t)
+}
+
+// Imports produces the import declaration
+func (i *goImports) Imports() string {
+	paths := make([]string, 0, len(i.aliases))
+	for p := range i.aliases {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var res strings.Builder
+	res.WriteString("import (\n")
+	for _, p := range paths {
+		if a := i.aliases[p]; a != path.Base(p) {
+			fmt.Fprintf(&res, "\t%s %q\n", a, p)
+		} else {
+			fmt.Fprintf(&res, "\t%q\n", p)
+		}
+	}
+	res.WriteString(")\n")
+	return res.String()
+}
+
+// RenderJSONRPCDispatchers produces Go code which routes JSON-RPC 2.0 requests to implementations of
+// the services (Go interfaces). pkgPath is the import path of the package the code is generated into.
+// Methods are named using the same rules as the clients produced by GenerateJSONRPCClients, so that the two interoperate.
+// If the clients' types were extracted using CustomNamer, pass the same namer using JSONRPCServiceNaming.
+func RenderJSONRPCDispatchers(pkgPath string, out io.Writer, services []interface{}, cfg ...JSONRPCOption) error {
+	opts := newJSONRPCOptions(cfg)
+	imports := newGoImports(pkgPath, "bytes", "encoding/json", "errors", "fmt", "net/http")
+
+	var body bytes.Buffer
+	for _, s := range services {
+		t := reflect.TypeOf(s)
+		if t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Interface {
+			return fmt.Errorf("can only produce dispatchers for interfaces, not %v", t)
+		}
+
+		if err := renderDispatcher(&body, t, imports, opts); err != nil {
+			return err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by github.com/32leaves/bel. DO NOT EDIT.\n\npackage %s\n\n", path.Base(pkgPath))
+	src.WriteString(imports.Imports())
+	src.WriteString(dispatcherRuntime)
+	body.WriteTo(&src)
+
+	fc, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("cannot format generated code: %v", err)
+	}
+	_, err = out.Write(fc)
+	return err
+}
+
+func renderDispatcher(out *bytes.Buffer, t reflect.Type, imports *goImports, opts JSONRPCOptions) error {
+	ifaceName, err := imports.typeExpr(t)
+	if err != nil {
+		return err
+	}
+	service := opts.ServiceNamer(t)
+	dispatcher := t.Name() + "Dispatcher"
+	errorInterface := reflect.TypeOf((*error)(nil)).Elem()
+
+	fmt.Fprintf(out, "\n// %s routes JSON-RPC 2.0 requests to an implementation of %s\n", dispatcher, ifaceName)
+	fmt.Fprintf(out, "type %s struct {\n\tmethods map[string]jsonrpcMethod\n}\n", dispatcher)
+	fmt.Fprintf(out, "\n// New%s creates a new dispatcher for impl\n", dispatcher)
+	fmt.Fprintf(out, "func New%s(impl %s) *%s {\n\treturn &%s{methods: map[string]jsonrpcMethod{\n", dispatcher, ifaceName, dispatcher, dispatcher)
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		fnt := m.Type
+		if fnt.IsVariadic() {
+			return fmt.Errorf("variadic functions are not supported: %s/%s", t.Name(), m.Name)
+		}
+
+		var (
+			hasResult bool
+			hasError  bool
+		)
+		switch fnt.NumOut() {
+		case 0:
+		case 1:
+			hasError = fnt.Out(0).Implements(errorInterface)
+			hasResult = !hasError
+		case 2:
+			if !fnt.Out(1).Implements(errorInterface) {
+				return fmt.Errorf("second return value must be an error in %s/%s", t.Name(), m.Name)
+			}
+			hasResult, hasError = true, true
+		default:
+			return fmt.Errorf("cannot export more than two return values in %s/%s", t.Name(), m.Name)
+		}
+
+		names := make([]string, fnt.NumIn())
+		for j := range names {
+			names[j] = strconv.Quote(fmt.Sprintf("arg%d", j))
+		}
+		fmt.Fprintf(out, "\t\t%q: func(params json.RawMessage) (interface{}, error) {\n", opts.MethodNamer(service, m.Name))
+		args := make([]string, fnt.NumIn())
+		ptrs := make([]string, fnt.NumIn())
+		for j := 0; j < fnt.NumIn(); j++ {
+			at, err := imports.typeExpr(fnt.In(j))
+			if err != nil {
+				return fmt.Errorf("%s/%s: %v", t.Name(), m.Name, err)
+			}
+			args[j] = fmt.Sprintf("arg%d", j)
+			ptrs[j] = "&" + args[j]
+			fmt.Fprintf(out, "\t\t\tvar %s %s\n", args[j], at)
+		}
+		fmt.Fprintf(out, "\t\t\tif err := decodeJSONRPCParams(params, []string{%s}%s); err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n",
+			strings.Join(names, ", "), strings.Join(append([]string{""}, ptrs...), ", "))
+
+		call := fmt.Sprintf("impl.%s(%s)", m.Name, strings.Join(args, ", "))
+		switch {
+		case hasResult && hasError:
+			fmt.Fprintf(out, "\t\t\treturn %s\n", call)
+		case hasResult:
+			fmt.Fprintf(out, "\t\t\treturn %s, nil\n", call)
+		case hasError:
+			fmt.Fprintf(out, "\t\t\treturn nil, %s\n", call)
+		default:
+			fmt.Fprintf(out, "\t\t\t%s\n\t\t\treturn nil, nil\n", call)
+		}
+		out.WriteString("\t\t},\n")
+	}
+	out.WriteString("\t}}\n}\n")
+
+	fmt.Fprintf(out, "\n// Dispatch handles a single or batch JSON-RPC request. It returns nil if there's nothing to respond.\n")
+	fmt.Fprintf(out, "func (d *%s) Dispatch(req []byte) []byte {\n\treturn dispatchJSONRPC(d.methods, req)\n}\n", dispatcher)
+	fmt.Fprintf(out, "\n// ServeHTTP handles JSON-RPC requests sent via HTTP POST\n")
+	fmt.Fprintf(out, `func (d *%s) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req bytes.Buffer
+	if _, err := req.ReadFrom(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := d.Dispatch(req.Bytes())
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+`, dispatcher)
+
+	return nil
+}
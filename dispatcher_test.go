@@ -0,0 +1,173 @@
+package bel
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type CalculatorService interface {
+	Add(a, b int) (int, error)
+	Divide(a, b float64) (float64, error)
+	Reset()
+}
+
+// calculatorProgram exercises a generated dispatcher. It is compiled as test of a copy of this package.
+const calculatorProgram = `package bel
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+type CalculatorService interface {
+	Add(a, b int) (int, error)
+	Divide(a, b float64) (float64, error)
+	Reset()
+}
+
+type calculator struct{}
+
+func (calculator) Add(a, b int) (int, error) { return a + b, nil }
+func (calculator) Divide(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, &JSONRPCError{Code: 1, Message: "division by zero"}
+	}
+	return a / b, nil
+}
+func (calculator) Reset() {}
+
+func TestMain(m *testing.M) {
+	d := NewCalculatorServiceDispatcher(calculator{})
+	for _, req := range []string{
+		` + "`" + `{"jsonrpc":"2.0","id":1,"method":"CalculatorService.Add","params":[1,2]}` + "`" + `,
+		` + "`" + `{"jsonrpc":"2.0","id":2,"method":"CalculatorService.Add","params":{"arg0":3,"arg1":4}}` + "`" + `,
+		` + "`" + `{"jsonrpc":"2.0","id":3,"method":"CalculatorService.Divide","params":[1,0]}` + "`" + `,
+		` + "`" + `{"jsonrpc":"2.0","id":4,"method":"CalculatorService.Add","params":[1]}` + "`" + `,
+		` + "`" + `{"jsonrpc":"2.0","id":5,"method":"Nope"}` + "`" + `,
+		` + "`" + `{"jsonrpc":"2.0","method":"CalculatorService.Reset"}` + "`" + `,
+		` + "`" + `[{"jsonrpc":"2.0","id":6,"method":"CalculatorService.Reset"},{"jsonrpc":"2.0","method":"CalculatorService.Reset"}]` + "`" + `,
+		` + "`" + `{"jsonrpc":` + "`" + `,
+	} {
+		fmt.Printf("%s\n", d.Dispatch([]byte(req)))
+	}
+	os.Exit(0)
+}
+`
+
+func TestJSONRPCDispatcher(t *testing.T) {
+	if testing.Short() {
+		t.Skip("running short test - skipping dispatcher compilation test")
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "bel-dispatcher")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	err = RenderJSONRPCDispatchers("github.com/32leaves/bel", &src, []interface{}{(*CalculatorService)(nil)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	files := map[string]string{
+		"go.mod":          "module github.com/32leaves/bel\n\ngo 1.13\n",
+		"dispatcher.go":   src.String(),
+		"program_test.go": calculatorProgram,
+	}
+	for fn, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, fn), []byte(content), 0644)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	build := exec.Command("go", "test", "-c", "-o", "program.test")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Errorf("cannot compile generated dispatcher: %v\n%s\n%s", err, out, src.String())
+		return
+	}
+	run := exec.Command(filepath.Join(dir, "program.test"))
+	out, err := run.CombinedOutput()
+	if err != nil {
+		t.Errorf("cannot run generated dispatcher: %v\n%s", err, out)
+		return
+	}
+
+	expectation := []string{
+		`{"jsonrpc":"2.0","id":1,"result":3}`,
+		`{"jsonrpc":"2.0","id":2,"result":7}`,
+		`{"jsonrpc":"2.0","id":3,"error":{"code":1,"message":"division by zero"}}`,
+		`{"jsonrpc":"2.0","id":4,"error":{"code":-32602,"message":"expected 2 params, got 1"}}`,
+		`{"jsonrpc":"2.0","id":5,"error":{"code":-32601,"message":"method not found: Nope"}}`,
+		``,
+		`[{"jsonrpc":"2.0","id":6,"result":null}]`,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"unexpected end of JSON input"}}`,
+	}
+	if act := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"); strings.Join(act, "\n") != strings.Join(expectation, "\n") {
+		t.Errorf("unexpected responses:\n%s", strings.Join(act, "\n"))
+	}
+}
+
+func TestJSONRPCDispatcherMethodNames(t *testing.T) {
+	var src bytes.Buffer
+	err := RenderJSONRPCDispatchers("github.com/32leaves/bel", &src, []interface{}{(*JSONRPCService)(nil)}, JSONRPCMethodNaming(PlainMethodNames))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, exp := range []string{
+		"func NewJSONRPCServiceDispatcher(impl JSONRPCService) *JSONRPCServiceDispatcher {",
+		`"ListStructs": func(params json.RawMessage) (interface{}, error) {`,
+		"var arg0 *AnotherTestStruct",
+		`"Ping": func(params json.RawMessage) (interface{}, error) {`,
+		"return nil, impl.Ping()",
+	} {
+		if !strings.Contains(src.String(), exp) {
+			t.Errorf("generated code does not contain %q:\n%s", exp, src.String())
+		}
+	}
+}
+
+func TestJSONRPCDispatcherServiceNames(t *testing.T) {
+	namer := func(t reflect.Type) string { return "I" + t.Name() }
+
+	types, err := Extract((*JSONRPCService)(nil), CustomNamer(namer))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var client bytes.Buffer
+	err = Render(types, GenerateJSONRPCClients(), GenerateOutputTo(&client))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var src bytes.Buffer
+	err = RenderJSONRPCDispatchers("github.com/32leaves/bel", &src, []interface{}{(*JSONRPCService)(nil)}, JSONRPCServiceNaming(namer))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if exp := `this.transport("IJSONRPCService.Ping"`; !strings.Contains(client.String(), exp) {
+		t.Errorf("client does not contain %q:\n%s", exp, client.String())
+	}
+	if exp := `"IJSONRPCService.Ping": func(params json.RawMessage) (interface{}, error) {`; !strings.Contains(src.String(), exp) {
+		t.Errorf("dispatcher does not contain %q:\n%s", exp, src.String())
+	}
+}
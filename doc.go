@@ -5,8 +5,7 @@ import (
 	"go/ast"
 	"go/doc"
 	"go/parser"
-	"go/token"
-	"os"
+	"io/fs"
 	"reflect"
 	"strings"
 )
@@ -20,6 +19,14 @@ type DocHandler interface {
 	Method(parent reflect.Type, method reflect.Method) string
 }
 
+// FieldDocHandler is a DocHandler which also provides documentation for struct fields
+type FieldDocHandler interface {
+	DocHandler
+
+	// Field retrieves documentation for a struct's field
+	Field(parent reflect.Type, field reflect.StructField) string
+}
+
 type nullDocHandler string
 
 func (*nullDocHandler) Type(t reflect.Type) string {
@@ -44,11 +51,29 @@ func NewParsedSourceDocHandler(srcdir, base string) (*ParsedSourceDocHandler, er
 	return res, nil
 }
 
+// NewParsedSourceDocHandlerFS creates a new doc handler with a single pkg of fsys in its index, e.g. from sources
+// embedded in a generator binary using go:embed. See AddToIndexFS.
+func NewParsedSourceDocHandlerFS(fsys fs.FS, srcdir, base string) (*ParsedSourceDocHandler, error) {
+	res := &ParsedSourceDocHandler{pkgs: make(map[string]*doc.Package)}
+	if err := res.AddToIndexFS(fsys, srcdir, base); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // AddToIndex adds another package to the handler's index. src is the path to the Go src folder of the package, pkg is its import path
 func (h *ParsedSourceDocHandler) AddToIndex(src, pkg string) error {
-	fset := token.NewFileSet()
+	return h.addToIndex(newOSSourceScanner(src, parser.ParseComments), ".", pkg)
+}
+
+// AddToIndexFS adds another package to the handler's index, reading its sources from fsys. src is the slash-separated
+// path of the package's folder in fsys, pkg is its import path.
+func (h *ParsedSourceDocHandler) AddToIndexFS(fsys fs.FS, src, pkg string) error {
+	return h.addToIndex(newSourceScanner(fsys, "", parser.ParseComments), src, pkg)
+}
 
-	ps, err := parser.ParseDir(fset, src, func(i os.FileInfo) bool { return true }, parser.ParseComments)
+func (h *ParsedSourceDocHandler) addToIndex(scanner *sourceScanner, src, pkg string) error {
+	ps, err := parseDocPackages(scanner, src)
 	if err != nil {
 		return err
 	}
@@ -63,18 +88,44 @@ func (h *ParsedSourceDocHandler) AddToIndex(src, pkg string) error {
 	return nil
 }
 
+// parseDocPackages parses the packages in src for their documentation, by package name
+func parseDocPackages(scanner *sourceScanner, src string) (map[string]*ast.Package, error) {
+	// documentation may come from any file of the package
+	scanner.tests = true
+	scanner.ctxt.UseAllFiles = true
+
+	ps, err := scanner.ParseDir(src)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*ast.Package, len(ps))
+	for n, files := range ps {
+		p := &ast.Package{Name: n, Files: make(map[string]*ast.File, len(files))}
+		for _, f := range files {
+			p.Files[scanner.fset.Position(f.Package).Filename] = f
+		}
+		res[n] = p
+	}
+	return res, nil
+}
+
 func (h *ParsedSourceDocHandler) findDoc(t reflect.Type) *doc.Type {
 	pkg, ok := h.pkgs[t.PkgPath()]
 	if !ok {
 		return nil
 	}
+	return findDocType(t.Name(), pkg)
+}
 
-	for _, doct := range pkg.Types {
-		if doct.Name == t.Name() {
-			return doct
+// findDocType returns the documentation of the named type in the first package which declares it
+func findDocType(name string, pkgs ...*doc.Package) *doc.Type {
+	for _, pkg := range pkgs {
+		for _, doct := range pkg.Types {
+			if doct.Name == name {
+				return doct
+			}
 		}
 	}
-
 	return nil
 }
 
@@ -94,26 +145,53 @@ func (h *ParsedSourceDocHandler) Method(parent reflect.Type, method reflect.Meth
 	if doct == nil {
 		return ""
 	}
+	return memberDocs(doct)[method.Name]
+}
 
-	specs := doct.Decl.Specs
-	if len(specs) < 1 {
-		return ""
-	}
-	tspec, ok := specs[0].(*ast.TypeSpec)
-	if !ok {
+// Field retrieves documentation for a struct field using the handler's index
+func (h *ParsedSourceDocHandler) Field(parent reflect.Type, field reflect.StructField) string {
+	doct := h.findDoc(parent)
+	if doct == nil {
 		return ""
 	}
+	return memberDocs(doct)[field.Name]
+}
 
-	ifspec, ok := tspec.Type.(*ast.InterfaceType)
+// memberDocs returns the documentation of the fields of a struct, or of the methods of an interface, by name.
+// Fields may be documented with a line comment, too.
+func memberDocs(doct *doc.Type) map[string]string {
+	res := make(map[string]string)
+	if len(doct.Decl.Specs) < 1 {
+		return res
+	}
+	tspec, ok := doct.Decl.Specs[0].(*ast.TypeSpec)
 	if !ok {
-		return ""
+		return res
 	}
 
-	for _, dm := range ifspec.Methods.List {
-		if len(dm.Names) > 0 && dm.Names[0].Name == method.Name {
-			return strings.TrimSpace(dm.Doc.Text())
+	switch tp := tspec.Type.(type) {
+	case *ast.InterfaceType:
+		for _, dm := range tp.Methods.List {
+			if len(dm.Names) > 0 {
+				res[dm.Names[0].Name] = docText(dm.Doc)
+			}
+		}
+	case *ast.StructType:
+		for _, f := range tp.Fields.List {
+			for _, n := range f.Names {
+				res[n.Name] = docText(f.Doc, f.Comment)
+			}
 		}
 	}
+	return res
+}
 
+// docText returns the text of the first non-empty comment group
+func docText(comments ...*ast.CommentGroup) string {
+	for _, cg := range comments {
+		if txt := strings.TrimSpace(cg.Text()); txt != "" {
+			return txt
+		}
+	}
 	return ""
 }
@@ -36,6 +36,8 @@ func TestParsedSourceDocHandler(t *testing.T) {
 			Name:    "DoSomethingReq",
 			Comment: "DoSomethingReq is a struct with documentation",
 			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "DoSomethingReq",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -45,6 +47,7 @@ func TestParsedSourceDocHandler(t *testing.T) {
 							Kind: TypescriptKind("simple"),
 						},
 					},
+					Comment: "Field has documentation as well",
 				},
 			},
 		},
@@ -52,6 +55,8 @@ func TestParsedSourceDocHandler(t *testing.T) {
 			Name:    "InterfaceWithDocumentation",
 			Comment: "InterfaceWithDocumentation has this documentation",
 			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "InterfaceWithDocumentation",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
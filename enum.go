@@ -3,11 +3,14 @@ package bel
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 // EnumHandler can determine if a type is an "enum" and retrieve its options
@@ -21,108 +24,493 @@ type EnumHandler interface {
 
 // ParsedSourceEnumHandler discovers enums from type and const statements
 type ParsedSourceEnumHandler struct {
+	// enums are indexed by the import path and name of their type, see enumKey
 	enums map[string][]TypescriptEnumMember
+
+	minMembers int
+	modulePath string
+	scanner    *sourceScanner
 }
 
-// NewParsedSourceEnumHandler creates a new enum handler that parses source code to discover enums
-func NewParsedSourceEnumHandler(srcdir string) (*ParsedSourceEnumHandler, error) {
-	fset := token.NewFileSet()
-	pkgs := make(map[string]*ast.Package)
-	err := filepath.Walk(srcdir, func(path string, info os.FileInfo, err error) error {
-		if !info.IsDir() {
-			return nil
-		}
+// ParsedSourceEnumOption configures a ParsedSourceEnumHandler
+type ParsedSourceEnumOption func(*ParsedSourceEnumHandler)
 
-		ps, err := parser.ParseDir(fset, path, func(i os.FileInfo) bool { return true }, 0)
-		if err != nil {
-			return err
-		}
-		for n, pkg := range ps {
-			pkgs[n] = pkg
+// MinEnumMembers sets the number of typed constants a type needs to be considered an enum. The default is 1.
+func MinEnumMembers(n int) ParsedSourceEnumOption {
+	return func(h *ParsedSourceEnumHandler) {
+		h.minMembers = n
+	}
+}
+
+// BuildConstraints selects the files scanned for enums using the build constraints of goos, goarch and tags.
+// By default the constraints of the current platform apply.
+func BuildConstraints(goos, goarch string, tags ...string) ParsedSourceEnumOption {
+	return func(h *ParsedSourceEnumHandler) {
+		h.scanner.ctxt.GOOS = goos
+		h.scanner.ctxt.GOARCH = goarch
+		h.scanner.ctxt.BuildTags = tags
+	}
+}
+
+// IncludeTestFiles scans _test.go files for enums, too
+func IncludeTestFiles(h *ParsedSourceEnumHandler) {
+	h.scanner.tests = true
+}
+
+// ModulePath sets the import path of the scanned root directory, for sources which are not part of a module,
+// e.g. sources embedded without their go.mod
+func ModulePath(path string) ParsedSourceEnumOption {
+	return func(h *ParsedSourceEnumHandler) {
+		h.modulePath = path
+	}
+}
+
+// enumKey identifies an enum type, e.g. github.com/32leaves/bel.MyEnum. Enums in directories whose
+// import path is unknown have an empty pkgPath.
+func enumKey(pkgPath, name string) string {
+	if pkgPath == "" {
+		return name
+	}
+	return pkgPath + "." + name
+}
+
+// NewParsedSourceEnumHandler creates a new enum handler that parses source code to discover enums.
+// Each directory is resolved to its import path using the go.mod of its module (or the GOPATH),
+// so that enums of the same name in different packages don't collide.
+//
+// Like the go tool, the handler skips vendor, testdata and node_modules directories, as well as files excluded
+// by build constraints (see BuildConstraints) and test files (see IncludeTestFiles).
+//
+// A type is an enum if there are typed constants of that type (see MinEnumMembers). Annotate a type
+// with a `//bel:noenum` comment to never treat it as an enum, or with `//bel:enum` to always do so.
+func NewParsedSourceEnumHandler(srcdir string, opts ...ParsedSourceEnumOption) (*ParsedSourceEnumHandler, error) {
+	resolver := newImportPathResolver()
+	scanner := newOSSourceScanner(srcdir, parser.ParseComments)
+	return newParsedSourceEnumHandler(scanner, ".", func(dir string) (string, bool) {
+		return resolver.ImportPath(filepath.Join(srcdir, filepath.FromSlash(dir)))
+	}, opts)
+}
+
+// NewParsedSourceEnumHandlerFS creates a new enum handler that parses the source code in the root directory of fsys,
+// e.g. sources embedded in a generator binary using go:embed. Import paths are resolved using the go.mod files in fsys,
+// or ModulePath if there are none. Otherwise it works like NewParsedSourceEnumHandler.
+func NewParsedSourceEnumHandlerFS(fsys fs.FS, root string, opts ...ParsedSourceEnumOption) (*ParsedSourceEnumHandler, error) {
+	scanner := newSourceScanner(fsys, "", parser.ParseComments)
+	return newParsedSourceEnumHandler(scanner, root, func(dir string) (string, bool) {
+		return fsImportPath(fsys, dir)
+	}, opts)
+}
+
+// newParsedSourceEnumHandler discovers the enums in the root directory of a scanner. resolve determines the import path of a directory.
+func newParsedSourceEnumHandler(scanner *sourceScanner, root string, resolve func(dir string) (string, bool), opts []ParsedSourceEnumOption) (*ParsedSourceEnumHandler, error) {
+	var (
+		enums = make(map[string][]TypescriptEnumMember)
+		res   = &ParsedSourceEnumHandler{enums: enums, minMembers: 1, scanner: scanner}
+	)
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	err := res.scanner.Walk(root, func(dir string, pkgs map[string][]*ast.File) error {
+		importPath, ok := resolve(dir)
+		if !ok && res.modulePath != "" {
+			rel := strings.TrimPrefix(strings.TrimPrefix(dir, root), "/")
+			if root == "." {
+				rel = dir
+			}
+			importPath = joinImportPath(res.modulePath, rel)
 		}
+		for n, files := range pkgs {
+			pkgPath := importPath
+			if pkgPath != "" && strings.HasSuffix(n, "_test") && len(pkgs) > 1 {
+				// external test packages have an import path of their own
+				pkgPath += "_test"
+			}
 
+			for name, members := range findEnums(files, res.minMembers) {
+				enums[enumKey(pkgPath, name)] = members
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	return res, nil
+}
+
+// findEnums returns the members of the enums declared in the files of a package, by type name
+func findEnums(files []*ast.File, minMembers int) map[string][]TypescriptEnumMember {
 	// the way the enum detection works at the moment this needs to be done in two passes
-	enums := make(map[string][]TypescriptEnumMember)
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			ast.Inspect(file, extractEnumTypes(enums))
-		}
+	candidates := make(map[string]*enumCandidate)
+	for _, file := range files {
+		ast.Inspect(file, extractEnumTypes(candidates))
 	}
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			ast.Inspect(file, extractEnumValues(enums))
+	extractEnumValues(candidates, findConsts(files))
+
+	res := make(map[string][]TypescriptEnumMember)
+	for name, c := range candidates {
+		if c.isEnum(minMembers) {
+			res[name] = c.members
 		}
 	}
+	return res
+}
+
+const (
+	// enumAnnotation marks a type as enum, regardless of its constants
+	enumAnnotation = "//bel:enum"
+	// noEnumAnnotation marks a type as not being an enum
+	noEnumAnnotation = "//bel:noenum"
+)
 
-	return &ParsedSourceEnumHandler{enums: enums}, nil
+// enumCandidate is a type which might be an enum
+type enumCandidate struct {
+	members []TypescriptEnumMember
+	// consts counts the typed constants of the type, including those whose value we cannot evaluate
+	consts     int
+	annotation string
 }
 
-func extractEnumTypes(enums map[string][]TypescriptEnumMember) func(node ast.Node) bool {
-	return func(node ast.Node) bool {
-		if ts, ok := node.(*ast.TypeSpec); ok {
-			enumName := ts.Name.Name
-			if _, ok := ts.Type.(*ast.Ident); ok {
-				enums[enumName] = make([]TypescriptEnumMember, 0)
+func (c *enumCandidate) isEnum(minMembers int) bool {
+	switch c.annotation {
+	case enumAnnotation:
+		return true
+	case noEnumAnnotation:
+		return false
+	}
+	return len(c.members) > 0 && c.consts >= minMembers
+}
+
+// findEnumAnnotation returns the enum annotation among the comments, if any
+func findEnumAnnotation(comments ...*ast.CommentGroup) string {
+	for _, cg := range comments {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			switch strings.TrimSpace(c.Text) {
+			case enumAnnotation:
+				return enumAnnotation
+			case noEnumAnnotation:
+				return noEnumAnnotation
 			}
+		}
+	}
+	return ""
+}
 
+func extractEnumTypes(candidates map[string]*enumCandidate) func(node ast.Node) bool {
+	return func(node ast.Node) bool {
+		decl, ok := node.(*ast.GenDecl)
+		if !ok {
+			return true
+		}
+		if decl.Tok != token.TYPE {
 			return false
 		}
 
-		return true
+		for _, spec := range decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if _, ok := ts.Type.(*ast.Ident); !ok {
+				continue
+			}
+
+			comments := []*ast.CommentGroup{ts.Doc, ts.Comment}
+			if !decl.Lparen.IsValid() {
+				// the doc comment of a single type declaration belongs to the declaration
+				comments = append(comments, decl.Doc)
+			}
+			candidates[ts.Name.Name] = &enumCandidate{
+				members:    make([]TypescriptEnumMember, 0),
+				annotation: findEnumAnnotation(comments...),
+			}
+		}
+		return false
 	}
 }
 
-func extractEnumValues(enums map[string][]TypescriptEnumMember) func(node ast.Node) bool {
-	return func(node ast.Node) bool {
-		if vs, ok := node.(*ast.ValueSpec); ok {
-			if len(vs.Names) < 1 || len(vs.Values) < 1 {
-				// TODO: add logging
-				return false
-			}
+// pkgConst is a constant declared in a package
+type pkgConst struct {
+	Name     string
+	Type     ast.Expr
+	Value    ast.Expr
+	Iota     int
+	Comments []*ast.CommentGroup
+}
 
-			var enumName string
-			if tp, ok := vs.Type.(*ast.Ident); ok {
-				enumName = tp.Name
-			} else {
-				return false
+// findConsts returns the constants declared in the files of a package, in the order they're declared
+func findConsts(files []*ast.File) []pkgConst {
+	var res []pkgConst
+	for _, file := range files {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				continue
 			}
 
-			if members, ok := enums[enumName]; ok {
-				name := vs.Names[0].Name
-				value := vs.Values[0]
-				if lit, ok := value.(*ast.BasicLit); ok {
-					members = append(members, TypescriptEnumMember{
-						Name:  name,
-						Value: lit.Value,
-					})
+			// specs without type and values repeat the previous ones, see https://golang.org/ref/spec#Constant_declarations
+			var (
+				tp     ast.Expr
+				values []ast.Expr
+			)
+			for iota, spec := range decl.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				if len(vs.Values) > 0 {
+					tp, values = vs.Type, vs.Values
+				}
+
+				comments := []*ast.CommentGroup{vs.Doc, vs.Comment}
+				if !decl.Lparen.IsValid() {
+					comments = append(comments, decl.Doc)
+				}
+				for i, name := range vs.Names {
+					if i >= len(values) || name.Name == "_" {
+						continue
+					}
+					res = append(res, pkgConst{Name: name.Name, Type: tp, Value: values[i], Iota: iota, Comments: comments})
 				}
-				enums[enumName] = members
 			}
-			return false
 		}
+	}
+	return res
+}
 
-		return true
+// evalConsts evaluates the constants of a package. Constants can refer to constants declared later or in other files,
+// hence we evaluate until there's no more progress.
+func evalConsts(consts []pkgConst) map[string]constant.Value {
+	res := make(map[string]constant.Value)
+	for progress := true; progress; {
+		progress = false
+		for _, c := range consts {
+			if _, done := res[c.Name]; done {
+				continue
+			}
+			if v, ok := evalConst(c.Value, c.Iota, res); ok {
+				res[c.Name] = v
+				progress = true
+			}
+		}
 	}
+	return res
 }
 
-// IsEnum returns true if the given type is an enumeration
-func (h *ParsedSourceEnumHandler) IsEnum(t reflect.Type) bool {
-	if _, ok := h.enums[t.Name()]; ok {
-		return true
+// extractEnumValues adds the constants to the enum candidates of their type
+func extractEnumValues(candidates map[string]*enumCandidate, consts []pkgConst) {
+	var (
+		values = evalConsts(consts)
+		byName = make(map[string]pkgConst, len(consts))
+		types  = make(map[string]string)
+	)
+	for _, c := range consts {
+		byName[c.Name] = c
+	}
+	// typeOf determines the type of a constant, which untyped ones inherit from the constants they refer to
+	var typeOf func(name string) string
+	typeOf = func(name string) string {
+		if tp, ok := types[name]; ok {
+			return tp
+		}
+		c, ok := byName[name]
+		if !ok {
+			return ""
+		}
+		// guard against cycles, which the compiler would reject anyway
+		types[name] = ""
+		types[name] = constType(c.Type, c.Value, typeOf)
+		return types[name]
+	}
+
+	for _, c := range consts {
+		cand := candidates[typeOf(c.Name)]
+		if cand == nil {
+			continue
+		}
+
+		cand.consts++
+		if lit, ok := enumValue(values[c.Name]); ok {
+			cand.members = append(cand.members, TypescriptEnumMember{
+				Name:    c.Name,
+				Value:   lit,
+				Comment: docText(c.Comments...),
+			})
+		}
 	}
+}
 
-	return false
+// evalConst evaluates a constant expression, e.g. `1 << iota`. Only literals, iota, other constants of the
+// package and operators are supported.
+func evalConst(expr ast.Expr, iota int, consts map[string]constant.Value) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		if e.Name == "iota" {
+			return constant.MakeInt64(int64(iota)), true
+		}
+		v, ok := consts[e.Name]
+		return v, ok
+	case *ast.ParenExpr:
+		return evalConst(e.X, iota, consts)
+	case *ast.CallExpr:
+		if conversionType(e) == "" {
+			return nil, false
+		}
+		return evalConst(e.Args[0], iota, consts)
+	case *ast.UnaryExpr:
+		x, ok := evalConst(e.X, iota, consts)
+		if !ok {
+			return nil, false
+		}
+		switch e.Op {
+		case token.ADD, token.SUB, token.XOR:
+			if x.Kind() != constant.Int && x.Kind() != constant.Float {
+				return nil, false
+			}
+			return constant.UnaryOp(e.Op, x, 0), true
+		}
+	case *ast.BinaryExpr:
+		x, ok := evalConst(e.X, iota, consts)
+		if !ok {
+			return nil, false
+		}
+		y, ok := evalConst(e.Y, iota, consts)
+		if !ok {
+			return nil, false
+		}
+		return evalBinaryOp(x, e.Op, y)
+	}
+	return nil, false
+}
+
+// constType returns the name of the type of a constant, declared either explicitly, by conversion (e.g. `Low = Level(1)`)
+// or by referring to typed constants (e.g. `High = Low + 1`)
+func constType(tp ast.Expr, value ast.Expr, identType func(name string) string) string {
+	if tp != nil {
+		if id, ok := tp.(*ast.Ident); ok {
+			return id.Name
+		}
+		return ""
+	}
+
+	// untyped constants take the type of the typed operands, see https://golang.org/ref/spec#Constant_expressions
+	switch e := value.(type) {
+	case *ast.CallExpr:
+		return conversionType(e)
+	case *ast.Ident:
+		return identType(e.Name)
+	case *ast.ParenExpr:
+		return constType(nil, e.X, identType)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return ""
+		}
+		return constType(nil, e.X, identType)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			// comparisons are untyped booleans
+			return ""
+		case token.SHL, token.SHR:
+			return constType(nil, e.X, identType)
+		}
+		if res := constType(nil, e.X, identType); res != "" {
+			return res
+		}
+		return constType(nil, e.Y, identType)
+	}
+	return ""
+}
+
+// conversionType returns the name of the type a call converts to, or "" if it's not a conversion
+func conversionType(call *ast.CallExpr) string {
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok || len(call.Args) != 1 {
+		return ""
+	}
+	switch id.Name {
+	case "len", "cap", "real", "imag", "min", "max":
+		// builtin functions which may appear in constant expressions
+		return ""
+	}
+	return id.Name
+}
+
+func evalBinaryOp(x constant.Value, op token.Token, y constant.Value) (res constant.Value, ok bool) {
+	switch op {
+	case token.SHL, token.SHR:
+		s, exact := constant.Uint64Val(constant.ToInt(y))
+		if !exact || x.Kind() != constant.Int {
+			return nil, false
+		}
+		return constant.Shift(x, op, uint(s)), true
+	case token.QUO, token.REM:
+		if constant.Sign(y) == 0 {
+			return nil, false
+		}
+		if op == token.QUO && x.Kind() == constant.Int && y.Kind() == constant.Int {
+			// integer division
+			op = token.QUO_ASSIGN
+		}
+	case token.ADD, token.SUB, token.MUL, token.AND, token.OR, token.XOR, token.AND_NOT:
+	default:
+		return nil, false
+	}
+
+	// BinaryOp panics for operands of mismatched kinds, e.g. "a" + 1
+	defer func() {
+		if recover() != nil {
+			res, ok = nil, false
+		}
+	}()
+	res = constant.BinaryOp(x, op, y)
+	return res, res.Kind() != constant.Unknown
+}
+
+// enumValue renders the value of an enum member as literal
+func enumValue(v constant.Value) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	switch v.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(v)), true
+	case constant.Int:
+		return v.ExactString(), true
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// lookup finds the members of an enum by the import path and name of its type. Enums whose
+// import path is unknown are matched by name only.
+func (h *ParsedSourceEnumHandler) lookup(t reflect.Type) ([]TypescriptEnumMember, bool) {
+	if members, ok := h.enums[enumKey(t.PkgPath(), t.Name())]; ok {
+		return members, true
+	}
+	members, ok := h.enums[enumKey("", t.Name())]
+	return members, ok
+}
+
+// IsEnum returns true if the given type is an enumeration
+func (h *ParsedSourceEnumHandler) IsEnum(t reflect.Type) bool {
+	_, ok := h.lookup(t)
+	return ok
 }
 
 // GetMember returns all members/values of an enum
 func (h *ParsedSourceEnumHandler) GetMember(t reflect.Type) ([]TypescriptEnumMember, error) {
-	if members, ok := h.enums[t.Name()]; ok {
+	if members, ok := h.lookup(t); ok {
 		return members, nil
 	}
 	return nil, fmt.Errorf("no enum %s found", t.Name())
@@ -1,6 +1,9 @@
 package bel
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -33,13 +36,13 @@ type StructWithEnum struct {
 }
 
 func TestParseStringEnum(t *testing.T) {
-	handler, err := NewParsedSourceEnumHandler(".")
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
-	myenum, ok := handler.enums["MyEnum"]
+	myenum, ok := handler.enums["github.com/32leaves/bel.MyEnum"]
 	if !ok {
 		t.Errorf("did not find MyEnum enum in sources")
 		return
@@ -67,13 +70,13 @@ func TestParseStringEnum(t *testing.T) {
 }
 
 func TestParseIntEnum(t *testing.T) {
-	handler, err := NewParsedSourceEnumHandler(".")
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
-	enum, ok := handler.enums["MyOtherEnum"]
+	enum, ok := handler.enums["github.com/32leaves/bel.MyOtherEnum"]
 	if !ok {
 		t.Errorf("did not find MyOtherEnum enum in sources")
 		return
@@ -104,8 +107,194 @@ func TestParseIntEnum(t *testing.T) {
 	}
 }
 
+func TestParseEnumsByImportPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n",
+		"a/state.go":     "package state\n\ntype State string\n\nconst Open State = \"open\"\n",
+		"b/state.go":     "package state\n\ntype State string\n\nconst Closed State = \"closed\"\n",
+		"nested/go.mod":  "module \"example.com/nested\" // a module of its own\n",
+		"nested/enum.go": "package nested\n\ntype State int\n\nconst Done State = 1\n",
+	}
+	if err := writeTestFiles(dir, files); err != nil {
+		t.Error(err)
+		return
+	}
+
+	handler, err := NewParsedSourceEnumHandler(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := map[string][]TypescriptEnumMember{
+		"example.com/app/a.State":  {{Name: "Open", Value: `"open"`}},
+		"example.com/app/b.State":  {{Name: "Closed", Value: `"closed"`}},
+		"example.com/nested.State": {{Name: "Done", Value: "1"}},
+	}
+	if diff := deep.Equal(handler.enums, expectation); diff != nil {
+		t.Error(diff)
+	}
+}
+
+// writeTestFiles writes files (by path relative to dir) and the directories they're in
+func writeTestFiles(dir string, files map[string]string) error {
+	for fn, content := range files {
+		fn = filepath.Join(dir, fn)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(fn, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEnumDetection(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"types.go": `package app
+
+type Duration int64
+
+var Timeout Duration = 5
+
+type Handler Other
+
+// Flag would be an enum, but is marked as none
+//bel:noenum
+type Flag string
+
+const FlagA Flag = "a"
+
+//bel:enum
+type Open string
+
+type (
+	Pair int
+	Single string
+)
+
+const (
+	PairA Pair = 1
+	PairB Pair = 2
+	SingleA Single = "a"
+)
+`,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Options     []ParsedSourceEnumOption
+		Expectation []string
+	}{
+		{"default", nil, []string{"example.com/app.Open", "example.com/app.Pair", "example.com/app.Single"}},
+		{"min members", []ParsedSourceEnumOption{MinEnumMembers(2)}, []string{"example.com/app.Open", "example.com/app.Pair"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handler, err := NewParsedSourceEnumHandler(dir, test.Options...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var act []string
+			for k := range handler.enums {
+				act = append(act, k)
+			}
+			sort.Strings(act)
+			if diff := deep.Equal(act, test.Expectation); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseEnumValues(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"values.go": `package app
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	_
+	Blue
+)
+
+type State string
+
+const (
+	On, Off State = "on", "off"
+	Unknown State = ` + "`unknown`" + `
+)
+
+type Size int64
+
+const (
+	KB Size = 1 << (10 * (iota + 1))
+	MB
+)
+
+const base = 10
+
+type Level int
+
+const (
+	Low    = Level(base / 4)
+	High   Level = -base
+	Broken Level = len("x")
+)
+`,
+		"a_priority.go": `package app
+
+type Priority int
+
+const (
+	Urgent Priority = first + iota
+	Normal
+)
+
+// Later is typed as it refers to Normal
+const Later = Normal + 1
+`,
+		// constants can refer to those declared in files read later
+		"z_base.go": "package app\n\nconst first = 1 << 2\n",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	handler, err := NewParsedSourceEnumHandler(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := map[string][]TypescriptEnumMember{
+		"example.com/app.Color":    {{Name: "Red", Value: "0"}, {Name: "Green", Value: "1"}, {Name: "Blue", Value: "3"}},
+		"example.com/app.State":    {{Name: "On", Value: `"on"`}, {Name: "Off", Value: `"off"`}, {Name: "Unknown", Value: `"unknown"`}},
+		"example.com/app.Size":     {{Name: "KB", Value: "1024"}, {Name: "MB", Value: "1048576"}},
+		"example.com/app.Level":    {{Name: "Low", Value: "2"}, {Name: "High", Value: "-10"}},
+		"example.com/app.Priority": {{Name: "Urgent", Value: "4"}, {Name: "Normal", Value: "5"}, {Name: "Later", Value: "6", Comment: "Later is typed as it refers to Normal"}},
+	}
+	if diff := deep.Equal(handler.enums, expectation); diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestExtractIntEnum(t *testing.T) {
-	handler, err := NewParsedSourceEnumHandler(".")
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
 	if err != nil {
 		t.Error(err)
 		return
@@ -124,8 +313,10 @@ func TestExtractIntEnum(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "MyEnum",
-			Kind: TypescriptKind("enum"),
+			Name:    "MyEnum",
+			Kind:    TypescriptKind("enum"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "MyEnum",
 			EnumMembers: []TypescriptEnumMember{
 				{
 					Name:  "MemberOne",
@@ -142,8 +333,10 @@ func TestExtractIntEnum(t *testing.T) {
 			},
 		},
 		{
-			Name: "MyOtherEnum",
-			Kind: TypescriptKind("enum"),
+			Name:    "MyOtherEnum",
+			Kind:    TypescriptKind("enum"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "MyOtherEnum",
 			EnumMembers: []TypescriptEnumMember{
 				{
 					Name:  "OtherEnumOne",
@@ -164,8 +357,10 @@ func TestExtractIntEnum(t *testing.T) {
 			},
 		},
 		{
-			Name: "StructWithEnum",
-			Kind: TypescriptKind("iface"),
+			Name:    "StructWithEnum",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "StructWithEnum",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/iancoleman/strcase"
 )
@@ -24,13 +25,25 @@ type extractor struct {
 	embedStructs    bool
 	followStructs   bool
 	noAnonStructs   bool
+	timeAsString    bool
 	sorter          func(a, b interface{}) bool
 	anonStructNamer AnonStructNamer
 	typeNamer       TypeNamer
 	enumHandler     EnumHandler
 	docHandler      DocHandler
-
-	result map[string]TypescriptType
+	sumTypeDecls    []sumType
+
+	// sumTypes and variants index the sum types declared using WithSumType
+	sumTypes map[reflect.Type]*sumType
+	variants map[reflect.Type]TypescriptMember
+
+	// origin is the package path of the struct we're currently extracting
+	origin string
+	// visiting contains the structs we're currently extracting, so that we can detect recursion
+	visiting map[reflect.Type]bool
+	result   map[string]TypescriptType
+	// order contains the names of the results in the order we found them, so that the result is deterministic
+	order []string
 }
 
 // EmbedStructs produces a single monolithic structure where all
@@ -75,13 +88,19 @@ func WithEnumerations(handler EnumHandler) ExtractOption {
 }
 
 // WithDocumentation configures a documentation handler which extracts documentation
-// for types and methods.
+// for types and methods, and for struct fields if the handler is a FieldDocHandler.
 func WithDocumentation(handler DocHandler) ExtractOption {
 	return func(e *extractor) {
 		e.docHandler = handler
 	}
 }
 
+// TimeAsString extracts time.Time as the RFC3339 string it marshals to, with the date-time format,
+// rather than as struct. Classes turn such strings into a Date.
+func TimeAsString(e *extractor) {
+	e.timeAsString = true
+}
+
 // SortAlphabetically sorts all types and their members alphabetically
 func SortAlphabetically(e *extractor) {
 	sorter := func(a, b interface{}) bool {
@@ -110,6 +129,9 @@ func SortAlphabetically(e *extractor) {
 }
 
 func (e *extractor) addResult(t *TypescriptType) {
+	if _, exists := e.result[t.Name]; !exists {
+		e.order = append(e.order, t.Name)
+	}
 	e.result[t.Name] = *t
 }
 
@@ -128,6 +150,10 @@ func Extract(s interface{}, opts ...ExtractOption) ([]TypescriptType, error) {
 	}
 
 	e.result = make(map[string]TypescriptType)
+	e.visiting = make(map[reflect.Type]bool)
+	if err := e.registerSumTypes(); err != nil {
+		return nil, err
+	}
 
 	t := reflect.TypeOf(s)
 	if t == nil {
@@ -142,6 +168,12 @@ func Extract(s interface{}, opts ...ExtractOption) ([]TypescriptType, error) {
 			return nil, err
 		}
 		e.addResult(estruct)
+	} else if st, ok := e.sumTypes[t]; ok {
+		et, err := e.extractSumType(st)
+		if err != nil {
+			return nil, err
+		}
+		e.addResult(et)
 	} else if t.Kind() == reflect.Interface {
 		et, err := e.extractInterface(t)
 		if err != nil {
@@ -152,9 +184,9 @@ func Extract(s interface{}, opts ...ExtractOption) ([]TypescriptType, error) {
 		return nil, fmt.Errorf("cannot extract TS interface from %v", t.Kind())
 	}
 
-	res := make([]TypescriptType, 0)
-	for _, e := range e.result {
-		res = append(res, e)
+	res := make([]TypescriptType, 0, len(e.order))
+	for _, name := range e.order {
+		res = append(res, e.result[name])
 	}
 	if e.sorter != nil {
 		sort.Slice(res, func(i, j int) bool {
@@ -228,6 +260,8 @@ func (e *extractor) extractInterface(t reflect.Type) (*TypescriptType, error) {
 	res := &TypescriptType{
 		Kind:    TypescriptInterfaceKind,
 		Name:    e.typeNamer(t),
+		PkgPath: t.PkgPath(),
+		GoName:  t.Name(),
 		Members: methods,
 		Comment: e.docHandler.Type(t),
 	}
@@ -235,6 +269,14 @@ func (e *extractor) extractInterface(t reflect.Type) (*TypescriptType, error) {
 }
 
 func (e *extractor) extractStruct(t reflect.Type) (*TypescriptType, error) {
+	if t.PkgPath() != "" {
+		// anonymous structs have no package path - they inherit the origin of their parent
+		defer func(origin string) { e.origin = origin }(e.origin)
+		e.origin = t.PkgPath()
+	}
+	e.visiting[t] = true
+	defer delete(e.visiting, t)
+
 	fields := make([]TypescriptMember, 0)
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -247,12 +289,16 @@ func (e *extractor) extractStruct(t reflect.Type) (*TypescriptType, error) {
 		if err != nil {
 			return nil, err
 		}
+		if docs, ok := e.docHandler.(FieldDocHandler); ok {
+			m.Comment = docs.Field(t, field)
+		}
 
 		// skip fields named "-", see https://golang.org/pkg/encoding/json/#Marshal
 		if m.Name != "-" {
 			fields = append(fields, *m)
 		}
 	}
+	fields = e.withDiscriminator(t, fields)
 
 	if e.sorter != nil {
 		sort.Slice(fields, func(i, j int) bool {
@@ -263,6 +309,8 @@ func (e *extractor) extractStruct(t reflect.Type) (*TypescriptType, error) {
 		Name:    e.typeNamer(t),
 		Comment: e.docHandler.Type(t),
 		Kind:    TypescriptInterfaceKind,
+		PkgPath: t.PkgPath(),
+		GoName:  t.Name(),
 		Members: fields,
 	}, nil
 }
@@ -285,8 +333,14 @@ func (e *extractor) extractStructField(t reflect.StructField) (*TypescriptMember
 			segments = segments[1:]
 		}
 		for _, seg := range segments {
-			if seg == "omitempty" {
+			switch seg {
+			case "omitempty":
 				optional = true
+			case "string":
+				// numbers and booleans are encoded as JSON strings, numbers keep their format
+				if tstype.Kind == TypescriptSimpleKind && (tstype.Name == "number" || tstype.Name == "boolean") {
+					tstype.Name = "string"
+				}
 			}
 		}
 	}
@@ -301,13 +355,20 @@ func (e *extractor) extractStructField(t reflect.StructField) (*TypescriptMember
 	}, nil
 }
 
+var timeType = reflect.TypeOf(time.Time{})
+
 func (e *extractor) getType(ttype reflect.Type, t *reflect.StructField) (*TypescriptType, error) {
 	var tstype *TypescriptType
 
+	nullable := false
 	if ttype.Kind() == reflect.Ptr {
 		ttype = ttype.Elem()
+		nullable = true
 	}
-	if ttype.Kind() == reflect.Struct {
+	if ttype == timeType && e.timeAsString {
+		// time.Time marshals to an RFC3339 string
+		tstype = &TypescriptType{Name: "string", Kind: TypescriptSimpleKind, Format: "date-time"}
+	} else if ttype.Kind() == reflect.Struct {
 		isanon := ttype.Name() == ""
 		if isanon {
 			astruct, err := e.extractStruct(ttype)
@@ -318,30 +379,51 @@ func (e *extractor) getType(ttype reflect.Type, t *reflect.StructField) (*Typesc
 			if e.noAnonStructs {
 				astructName := e.anonStructNamer(*t)
 				astruct.Name = astructName
+				astruct.PkgPath = e.origin
 				e.addResult(astruct)
 				tstype = &TypescriptType{Name: astructName, Kind: TypescriptSimpleKind}
 			} else {
 				tstype = astruct
 			}
 		} else if e.embedStructs {
+			if e.visiting[ttype] {
+				return nil, fmt.Errorf("cannot embed recursive struct %s", ttype.Name())
+			}
+
 			astruct, err := e.extractStruct(ttype)
 			if err != nil {
 				return nil, err
 			}
 
 			astruct.Name = ""
+			astruct.PkgPath = ""
+			astruct.GoName = ""
 			tstype = astruct
 		} else if e.followStructs {
-			astruct, err := e.extractStruct(ttype)
-			if err != nil {
-				return nil, err
+			// recursive structs refer to themselves while we're still extracting them
+			if !e.visiting[ttype] {
+				astruct, err := e.extractStruct(ttype)
+				if err != nil {
+					return nil, err
+				}
+				e.addResult(astruct)
 			}
 
-			e.addResult(astruct)
-			tstype = &TypescriptType{Name: astruct.Name, Kind: TypescriptSimpleKind}
+			tstype = &TypescriptType{Name: e.typeNamer(ttype), Kind: TypescriptSimpleKind}
 		} else {
 			tstype = &TypescriptType{Name: e.typeNamer(ttype), Kind: TypescriptSimpleKind}
 		}
+	} else if st, ok := e.sumTypes[ttype]; ok {
+		union, err := e.extractSumType(st)
+		if err != nil {
+			return nil, err
+		}
+		if e.embedStructs {
+			tstype = union
+		} else {
+			e.addResult(union)
+			tstype = &TypescriptType{Name: union.Name, Kind: TypescriptSimpleKind}
+		}
 	} else if e.enumHandler != nil && e.enumHandler.IsEnum(ttype) {
 		em, err := e.enumHandler.GetMember(ttype)
 		if err != nil {
@@ -350,6 +432,8 @@ func (e *extractor) getType(ttype reflect.Type, t *reflect.StructField) (*Typesc
 		enum := &TypescriptType{
 			Name:        e.typeNamer(ttype),
 			Kind:        TypescriptEnumKind,
+			PkgPath:     ttype.PkgPath(),
+			GoName:      ttype.Name(),
 			EnumMembers: em,
 		}
 		e.addResult(enum)
@@ -362,6 +446,9 @@ func (e *extractor) getType(ttype reflect.Type, t *reflect.StructField) (*Typesc
 		tstype = res
 	}
 
+	if nullable {
+		tstype.IsNullable = true
+	}
 	return tstype, nil
 }
 
@@ -372,6 +459,11 @@ func (e *extractor) getPrimitiveType(t reflect.Type) (*TypescriptType, error) {
 			Name: n,
 		}
 	}
+	mknumber := func(format string) *TypescriptType {
+		res := mktype("number")
+		res.Format = format
+		return res
+	}
 
 	kind := t.Kind()
 	switch kind {
@@ -387,19 +479,23 @@ func (e *extractor) getPrimitiveType(t reflect.Type) (*TypescriptType, error) {
 			Kind:   TypescriptArrayKind,
 			Params: []TypescriptType{*elem},
 		}, nil
-	case reflect.Float32,
-		reflect.Float64,
-		reflect.Int,
-		reflect.Int8,
+	case reflect.Float32:
+		return mknumber("float"), nil
+	case reflect.Float64:
+		return mknumber("double"), nil
+	case reflect.Int8,
 		reflect.Int16,
 		reflect.Int32,
-		reflect.Int64,
-		reflect.Uint,
 		reflect.Uint8,
-		reflect.Uint16,
-		reflect.Uint32,
+		reflect.Uint16:
+		return mknumber("int32"), nil
+	case reflect.Int,
+		reflect.Int64,
+		reflect.Uint32:
+		return mknumber("int64"), nil
+	case reflect.Uint,
 		reflect.Uint64:
-		return mktype("number"), nil
+		return mknumber("uint64"), nil
 	case reflect.Map:
 		key, err := e.getType(t.Key(), nil)
 		if err != nil {
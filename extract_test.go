@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 )
@@ -71,8 +72,10 @@ func TestExtractStruct(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "MyTestStruct",
-			Kind: TypescriptKind("iface"),
+			Name:    "MyTestStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "MyTestStruct",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -97,8 +100,9 @@ func TestExtractStruct(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "thisFieldIsNamed",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -106,8 +110,9 @@ func TestExtractStruct(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "thisIsOptional",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 					IsOptional: true,
@@ -125,8 +130,9 @@ func TestExtractStruct(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Referece",
 						Type: TypescriptType{
-							Name: "AnotherTestStruct",
-							Kind: TypescriptKind("simple"),
+							Name:       "AnotherTestStruct",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 				},
@@ -155,8 +161,9 @@ func TestNameAnonStructs(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "Anon",
-			Kind: TypescriptKind("iface"),
+			Name:    "Anon",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -170,8 +177,10 @@ func TestNameAnonStructs(t *testing.T) {
 			},
 		},
 		{
-			Name: "NestedStruct",
-			Kind: TypescriptKind("iface"),
+			Name:    "NestedStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "NestedStruct",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -186,8 +195,9 @@ func TestNameAnonStructs(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Refers",
 						Type: TypescriptType{
-							Name: "AnotherTestStruct",
-							Kind: TypescriptKind("simple"),
+							Name:       "AnotherTestStruct",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 				},
@@ -223,8 +233,10 @@ func TestFollowStruct(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "AnotherTestStruct",
-			Kind: TypescriptKind("iface"),
+			Name:    "AnotherTestStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "AnotherTestStruct",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -247,8 +259,10 @@ func TestFollowStruct(t *testing.T) {
 			},
 		},
 		{
-			Name: "NestedStruct",
-			Kind: TypescriptKind("iface"),
+			Name:    "NestedStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "NestedStruct",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -263,8 +277,9 @@ func TestFollowStruct(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Refers",
 						Type: TypescriptType{
-							Name: "AnotherTestStruct",
-							Kind: TypescriptKind("simple"),
+							Name:       "AnotherTestStruct",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 				},
@@ -310,8 +325,10 @@ func TestEmbeddStruct(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "NestedStruct",
-			Kind: TypescriptKind("iface"),
+			Name:    "NestedStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "NestedStruct",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -345,7 +362,8 @@ func TestEmbeddStruct(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Refers",
 						Type: TypescriptType{
-							Kind: TypescriptKind("iface"),
+							Kind:       TypescriptKind("iface"),
+							IsNullable: true,
 							Members: []TypescriptMember{
 								{
 									TypedElement: TypedElement{
@@ -406,8 +424,10 @@ func TestStructOfAllKind(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "StructOfAllKind",
-			Kind: TypescriptKind("iface"),
+			Name:    "StructOfAllKind",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "StructOfAllKind",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -436,8 +456,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Float32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "float",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -445,8 +466,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Float64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "double",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -454,8 +476,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "IntMember",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -463,8 +486,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int8Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -472,8 +496,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int16Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -481,8 +506,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -490,8 +516,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -499,8 +526,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "UintMember",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "uint64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -508,8 +536,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint8Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -517,8 +546,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint16Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -526,8 +556,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -535,8 +566,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "uint64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -551,8 +583,9 @@ func TestStructOfAllKind(t *testing.T) {
 									Kind: TypescriptKind("simple"),
 								},
 								{
-									Name: "number",
-									Kind: TypescriptKind("simple"),
+									Name:   "number",
+									Format: "int64",
+									Kind:   TypescriptKind("simple"),
 								},
 							},
 						},
@@ -562,8 +595,9 @@ func TestStructOfAllKind(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "PtrMember",
 						Type: TypescriptType{
-							Name: "string",
-							Kind: TypescriptKind("simple"),
+							Name:       "string",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 				},
@@ -595,8 +629,9 @@ func TestStructOfAllKind(t *testing.T) {
 									TypedElement: TypedElement{
 										Name: "AnotherAnonMember",
 										Type: TypescriptType{
-											Name: "number",
-											Kind: TypescriptKind("simple"),
+											Name:   "number",
+											Format: "int32",
+											Kind:   TypescriptKind("simple"),
 										},
 									},
 								},
@@ -627,15 +662,18 @@ func TestExtractInterface(t *testing.T) {
 	// repr.Print(extract)
 	expectation := []TypescriptType{
 		{
-			Name: "MyInterface",
-			Kind: TypescriptKind("iface"),
+			Name:    "MyInterface",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "MyInterface",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
 						Name: "FirstOp",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 					IsFunction: true,
@@ -653,8 +691,9 @@ func TestExtractInterface(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "SecondOp",
 						Type: TypescriptType{
-							Name: "StructOfAllKind",
-							Kind: TypescriptKind("simple"),
+							Name:       "StructOfAllKind",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 					IsFunction: true,
@@ -662,15 +701,17 @@ func TestExtractInterface(t *testing.T) {
 						{
 							Name: "arg0",
 							Type: TypescriptType{
-								Name: "number",
-								Kind: TypescriptKind("simple"),
+								Name:   "number",
+								Format: "int32",
+								Kind:   TypescriptKind("simple"),
 							},
 						},
 						{
 							Name: "arg1",
 							Type: TypescriptType{
-								Name: "StructOfAllKind",
-								Kind: TypescriptKind("simple"),
+								Name:       "StructOfAllKind",
+								Kind:       TypescriptKind("simple"),
+								IsNullable: true,
 							},
 						},
 					},
@@ -706,8 +747,10 @@ func TestSortAlphabetically(t *testing.T) {
 
 	expectation := []TypescriptType{
 		{
-			Name: "ATypeStartingWithA",
-			Kind: TypescriptKind("iface"),
+			Name:    "ATypeStartingWithA",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "ATypeStartingWithA",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -727,8 +770,9 @@ func TestSortAlphabetically(t *testing.T) {
 						{
 							Name: "arg0",
 							Type: TypescriptType{
-								Name: "StructOfAllKind",
-								Kind: TypescriptKind("simple"),
+								Name:       "StructOfAllKind",
+								Kind:       TypescriptKind("simple"),
+								IsNullable: true,
 							},
 						},
 					},
@@ -736,8 +780,10 @@ func TestSortAlphabetically(t *testing.T) {
 			},
 		},
 		{
-			Name: "StructOfAllKind",
-			Kind: TypescriptKind("iface"),
+			Name:    "StructOfAllKind",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "StructOfAllKind",
 			Members: []TypescriptMember{
 				{
 					TypedElement: TypedElement{
@@ -758,8 +804,9 @@ func TestSortAlphabetically(t *testing.T) {
 									TypedElement: TypedElement{
 										Name: "AnotherAnonMember",
 										Type: TypescriptType{
-											Name: "number",
-											Kind: TypescriptKind("simple"),
+											Name:   "number",
+											Format: "int32",
+											Kind:   TypescriptKind("simple"),
 										},
 									},
 								},
@@ -780,8 +827,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Float32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "float",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -789,8 +837,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Float64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "double",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -798,8 +847,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int16Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -807,8 +857,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -816,8 +867,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -825,8 +877,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Int8Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -834,8 +887,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "IntMember",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -850,8 +904,9 @@ func TestSortAlphabetically(t *testing.T) {
 									Kind: TypescriptKind("simple"),
 								},
 								{
-									Name: "number",
-									Kind: TypescriptKind("simple"),
+									Name:   "number",
+									Format: "int64",
+									Kind:   TypescriptKind("simple"),
 								},
 							},
 						},
@@ -861,8 +916,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "PtrMember",
 						Type: TypescriptType{
-							Name: "string",
-							Kind: TypescriptKind("simple"),
+							Name:       "string",
+							Kind:       TypescriptKind("simple"),
+							IsNullable: true,
 						},
 					},
 				},
@@ -893,8 +949,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint16Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -902,8 +959,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint32Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -911,8 +969,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint64Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "uint64",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -920,8 +979,9 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "Uint8Member",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "int32",
+							Kind:   TypescriptKind("simple"),
 						},
 					},
 				},
@@ -929,8 +989,50 @@ func TestSortAlphabetically(t *testing.T) {
 					TypedElement: TypedElement{
 						Name: "UintMember",
 						Type: TypescriptType{
-							Name: "number",
-							Kind: TypescriptKind("simple"),
+							Name:   "number",
+							Format: "uint64",
+							Kind:   TypescriptKind("simple"),
+						},
+					},
+				},
+			},
+		},
+	}
+	diff := deep.Equal(expectation, extract)
+	for _, d := range diff {
+		t.Error(d)
+	}
+}
+
+type RecursiveStruct struct {
+	Children []RecursiveStruct
+}
+
+func TestExtractRecursiveStruct(t *testing.T) {
+	extract, err := Extract(RecursiveStruct{}, FollowStructs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := []TypescriptType{
+		{
+			Name:    "RecursiveStruct",
+			Kind:    TypescriptKind("iface"),
+			PkgPath: "github.com/32leaves/bel",
+			GoName:  "RecursiveStruct",
+			Members: []TypescriptMember{
+				{
+					TypedElement: TypedElement{
+						Name: "Children",
+						Type: TypescriptType{
+							Kind: TypescriptKind("array"),
+							Params: []TypescriptType{
+								{
+									Name: "RecursiveStruct",
+									Kind: TypescriptKind("simple"),
+								},
+							},
 						},
 					},
 				},
@@ -941,4 +1043,36 @@ func TestSortAlphabetically(t *testing.T) {
 	for _, d := range diff {
 		t.Error(d)
 	}
+
+	_, err = Extract(RecursiveStruct{}, EmbedStructs)
+	if err == nil {
+		t.Error("expected an error when embedding a recursive struct")
+	}
+}
+
+type StructWithTime struct {
+	At time.Time
+}
+
+func TestTimeAsString(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Opts        []ExtractOption
+		Expectation TypescriptType
+	}{
+		{"default", nil, TypescriptType{Name: "Time", Kind: TypescriptSimpleKind}},
+		{"string", []ExtractOption{TimeAsString}, TypescriptType{Name: "string", Kind: TypescriptSimpleKind, Format: "date-time"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			extract, err := Extract(StructWithTime{}, test.Opts...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if diff := deep.Equal(extract[0].Members[0].Type, test.Expectation); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
 }
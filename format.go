@@ -0,0 +1,192 @@
+package bel
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Format determines the code style of the TypeScript code Render produces
+type Format struct {
+	// Indent is the string used for one level of indentation, e.g. four spaces or a tab
+	Indent string
+	// MemberTerminator ends each member of an interface, e.g. ";" or ","
+	MemberTerminator string
+	// TrailingCommas adds a comma after the last member of an enum
+	TrailingCommas bool
+	// SingleQuotes uses single instead of double quotes for string literals
+	SingleQuotes bool
+	// LineEnding ends each line, e.g. "\n" or "\r\n"
+	LineEnding string
+}
+
+var (
+	// DefaultFormat is the format Render uses unless configured otherwise
+	DefaultFormat = Format{
+		Indent:         "    ",
+		TrailingCommas: true,
+		LineEnding:     "\n",
+	}
+
+	// PrettierFormat matches the default settings of Prettier
+	PrettierFormat = Format{
+		Indent:           "  ",
+		MemberTerminator: ";",
+		TrailingCommas:   true,
+		LineEnding:       "\n",
+	}
+)
+
+// GenerateFormat configures the code style of the generated TypeScript code
+func GenerateFormat(f Format) GenerateOption {
+	return func(opt *GenerateOptions) {
+		opt.Format = f
+	}
+}
+
+// templateIndent is the indentation used by the templates and runtime code
+const templateIndent = "    "
+
+// formattingWriter applies a Format to the lines written to it. It drops blank lines,
+// except for a single one where the input has several in a row.
+type formattingWriter struct {
+	out        *bufio.Writer
+	format     Format
+	line       []byte
+	written    bool
+	emptylines int
+	err        error
+}
+
+func newFormattingWriter(out io.Writer, format Format) *formattingWriter {
+	return &formattingWriter{out: bufio.NewWriter(out), format: format}
+}
+
+// Write processes all complete lines in p and buffers the remainder
+func (w *formattingWriter) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	for _, c := range p {
+		if c != '\n' {
+			w.line = append(w.line, c)
+			continue
+		}
+		w.writeLine()
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+func (w *formattingWriter) writeLine() {
+	line := string(bytes.TrimRight(w.line, " \t\r"))
+	w.line = w.line[:0]
+	if line == "" {
+		w.emptylines++
+		return
+	}
+
+	if w.emptylines > 1 && w.written {
+		w.write(w.format.LineEnding)
+	}
+	w.emptylines = 0
+	w.written = true
+	w.write(w.formatLine(line))
+	w.write(w.format.LineEnding)
+}
+
+// formatLine re-indents a line and changes the quotes of its string literals
+func (w *formattingWriter) formatLine(line string) string {
+	line = reindent(line, w.format.Indent)
+	if w.format.SingleQuotes {
+		line = quoteLine(line)
+	}
+	return line
+}
+
+// reindent replaces the templateIndent at the beginning of a line with indent
+func reindent(line, indent string) string {
+	content := strings.TrimLeft(line, " ")
+	n := len(line) - len(content)
+	return strings.Repeat(indent, n/len(templateIndent)) + strings.Repeat(" ", n%len(templateIndent)) + content
+}
+
+// quoteLine uses single quotes for the string literals of a line, unless it's part of a comment
+func quoteLine(line string) string {
+	if isCommentLine(strings.TrimSpace(line)) {
+		return line
+	}
+	return singleQuotes(line)
+}
+
+func (w *formattingWriter) write(s string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.out.WriteString(s)
+}
+
+// Flush processes the remaining partial line and writes all buffered output
+func (w *formattingWriter) Flush() error {
+	if len(w.line) > 0 {
+		w.writeLine()
+	}
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.out.Flush()
+	return w.err
+}
+
+// isCommentLine returns true if a (trimmed) line is part of a comment
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*")
+}
+
+// singleQuotes turns double-quoted string literals in a line of code into single-quoted ones.
+// Anything following a line comment is left alone.
+func singleQuotes(line string) string {
+	var (
+		res   strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == 0 && c == '/' && i+1 < len(line) && line[i+1] == '/':
+			res.WriteString(line[i:])
+			return res.String()
+		case quote == 0 && c == '"':
+			quote = c
+			res.WriteByte('\'')
+		case quote == 0 && (c == '\'' || c == '`'):
+			quote = c
+			res.WriteByte(c)
+		case quote == 0:
+			res.WriteByte(c)
+		case c == '\\' && i+1 < len(line):
+			i++
+			if quote == '"' && line[i] == '"' {
+				res.WriteByte('"')
+			} else {
+				res.WriteByte(c)
+				res.WriteByte(line[i])
+			}
+		case c == quote:
+			quote = 0
+			if c == '"' {
+				c = '\''
+			}
+			res.WriteByte(c)
+		case quote == '"' && c == '\'':
+			res.WriteString("\\'")
+		default:
+			res.WriteByte(c)
+		}
+	}
+	return res.String()
+}
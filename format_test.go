@@ -0,0 +1,118 @@
+package bel
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenderFormat(t *testing.T) {
+	types := []TypescriptType{
+		{
+			Name: "Color",
+			Kind: TypescriptEnumKind,
+			EnumMembers: []TypescriptEnumMember{
+				{Name: "Red", Value: `"red"`},
+				{Name: "Blue", Value: `"it's blue"`},
+			},
+		},
+		{
+			Name:    "Shape",
+			Kind:    TypescriptInterfaceKind,
+			Comment: `a "shape"`,
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "color", Type: TypescriptType{Name: "Color", Kind: TypescriptSimpleKind}}},
+				{TypedElement: TypedElement{Name: "size", Type: TypescriptType{Name: "number", Kind: TypescriptSimpleKind}}, IsOptional: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name        string
+		Format      Format
+		Expectation string
+	}{
+		{
+			"default",
+			DefaultFormat,
+			"export enum Color {\n    Red = \"red\",\n    Blue = \"it's blue\",\n}\n\n/**\n * a \"shape\"\n */\nexport interface Shape {\n    color: Color\n    size?: number\n}\n",
+		},
+		{
+			"prettier",
+			PrettierFormat,
+			"export enum Color {\n  Red = \"red\",\n  Blue = \"it's blue\",\n}\n\n/**\n * a \"shape\"\n */\nexport interface Shape {\n  color: Color;\n  size?: number;\n}\n",
+		},
+		{
+			"tabs, single quotes and CRLF",
+			Format{Indent: "\t", MemberTerminator: ",", SingleQuotes: true, LineEnding: "\r\n"},
+			"export enum Color {\r\n\tRed = 'red',\r\n\tBlue = 'it\\'s blue'\r\n}\r\n\r\n/**\r\n * a \"shape\"\r\n */\r\nexport interface Shape {\r\n\tcolor: Color,\r\n\tsize?: number,\r\n}\r\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Render(types, GenerateFormat(test.Format), GeneratePreamble(""), GenerateOutputTo(&out))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if out.String() != test.Expectation {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestFormattingWriter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Format      Format
+		Input       []string
+		Expectation string
+	}{
+		{"single blank lines", DefaultFormat, []string{"a\n\nb\n"}, "a\nb\n"},
+		{"multiple blank lines", DefaultFormat, []string{"a\n  \n\nb\n\n\n"}, "a\n\nb\n"},
+		{"leading blank lines", DefaultFormat, []string{"\n\n\na\n"}, "a\n"},
+		{"split writes", DefaultFormat, []string{"fo", "o\nb", "ar"}, "foo\nbar\n"},
+		{"trailing whitespace", DefaultFormat, []string{"a  \nb\t\n"}, "a\nb\n"},
+		{"indentation", PrettierFormat, []string{"a\n    b\n         * c\n"}, "a\n  b\n     * c\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := newFormattingWriter(&out, test.Format)
+			for _, in := range test.Input {
+				if _, err := w.Write([]byte(in)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Error(err)
+				return
+			}
+			if out.String() != test.Expectation {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestSingleQuotes(t *testing.T) {
+	tests := []struct {
+		Input       string
+		Expectation string
+	}{
+		{`a = "b";`, `a = 'b';`},
+		{`a = "it's";`, `a = 'it\'s';`},
+		{`a = "say \"hi\"";`, `a = 'say "hi"';`},
+		{`a = 'b' + "c";`, `a = 'b' + 'c';`},
+		{"a = `\"b\"`;", "a = `\"b\"`;"},
+		{`a = "b"; // "c"`, `a = 'b'; // "c"`},
+		{`a = "\\";`, `a = '\\';`},
+	}
+	for _, test := range tests {
+		if act := singleQuotes(test.Input); act != test.Expectation {
+			t.Errorf("singleQuotes(%s): expected %s, got %s", test.Input, test.Expectation, act)
+		}
+	}
+}
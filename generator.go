@@ -1,12 +1,10 @@
 package bel
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"os"
-	"strings"
 	"text/template"
 	"time"
 )
@@ -23,84 +21,233 @@ const interfaceTemplate = `
 {
     {{ range .Members -}}
     {{- template "comment" . -}}
-    {{ .Name }}{{ if .IsOptional }}?{{ end }}{{ if .IsFunction }}({{ template "args" . }}){{ end }}: {{ subt .Type | default "void" }}
+    {{ .Name }}{{ if .IsOptional }}?{{ end }}{{ if .IsFunction }}({{ template "args" . }}){{ end }}: {{ subt .Type | default "void" | promise .IsFunction }}{{ terminator }}
     {{ end }}
 }
 {{ end -}}
 {{- define "args" }}{{ range $idx, $val := .Args }}{{ if eq $idx 0 }}{{ else }}, {{ end }}{{ .Name }}: {{ subt .Type }}{{ end }}{{ end -}}
 {{- define "simple" }}{{ .Name }}{{ end -}}
 {{- define "map" }}{ [key: {{ subt (mapKeyType .) }}]: {{ subt (mapValType .) }} }{{ end -}}
-{{- define "array" }}{{ subt (arrType .) }}[]{{ end -}}
-{{- define "root-enum" }}{{- template "comment" . -}}export enum {{ .Name }} {
-    {{ range .EnumMembers }}{{ .Name }} = {{ .Value }},
+{{- define "array" }}{{ with arrType . }}{{ if eq .Kind "union" }}({{ subt . }}){{ else }}{{ subt . }}{{ end }}{{ end }}[]{{ end -}}
+{{- define "literal" }}{{ .Name }}{{ end -}}
+{{- define "union" }}{{ range $idx, $val := .Params }}{{ if eq $idx 0 }}{{ else }} | {{ end }}{{ subt . }}{{ else }}never{{ end }}{{ end -}}
+{{- define "root-enum" }}{{- template "comment" . -}}export {{ declare }}enum {{ .Name }} {
+    {{ range $idx, $val := .EnumMembers }}{{ .Name }} = {{ .Value }}{{ enumSeparator $idx (len $.EnumMembers) }}
     {{ end }}
 }{{ end -}}
 {{- define "root-st-enum" }}{{- template "comment" . -}}export type {{ .Name }} =
     {{ range $idx, $val := .EnumMembers }}{{ if eq $idx 0 }}{{ else }} | {{ end }}{{ .Value }}{{ end }};
 {{ end -}}
-{{- define "root-iface" }}{{- template "comment" . -}}export interface {{ .Name }} {{ template "iface" . }} {{ end -}}
+{{- define "root-union" }}{{- template "comment" . -}}export type {{ .Name }} =
+    {{ template "union" . }};
+{{ end -}}
+{{- define "root-iface" }}{{- template "comment" . -}}export interface {{ .Name }} {{ template "iface" . }}{{ end -}}
 {{- .Preamble }}
-{{ if .Namespace }}export namespace {{ .Namespace }} {
+{{ if .Namespace }}export {{ declareNamespace }}namespace {{ .Namespace }} {
     {{ end -}}
+{{ jsonrpcRuntime }}
+{{ mockRuntime }}
 {{- range .Types }}
-{{ subtroot . }}
+{{ if isClass . }}{{ class . }}{{ else }}{{ subtroot . }}{{ end }}
+{{ guard . }}
+{{ client . }}
+{{ mock . }}
 {{ end -}}
-{{ if .Namespace }} } {{ end }}
+{{ if .Namespace }}}{{ end }}
 `
 
-type generateOptions struct {
-	enumsAsSumTypes bool
-	out             io.Writer
+// GenerateOptions configures code generation. Renderers receive them along with the types to render.
+type GenerateOptions struct {
+	EnumsAsSumTypes bool
+	ESModule        bool
+	Declarations    bool
+	ModuleFacades   bool
+	JSON            bool
+	TypeGuards      bool
+	Classes         bool
+	JSONRPC         *JSONRPCOptions
+	Mocks           bool
+	Out             io.Writer
 	Namespace       string
 	Types           []TypescriptType
 	Preamble        string
+	Format          Format
+
+	// Renderer produces the output, Typescript by default
+	Renderer Renderer
+	// Templates overrides named sub-templates of the TemplateRenderer
+	Templates map[string]string
+
+	// allTypes are the types the rendered ones may refer to, if they're rendered in parts (see RenderModules)
+	allTypes []TypescriptType
+	// externalRuntime is set if the runtime code is rendered separately and imported, see RenderModules
+	externalRuntime bool
 }
 
 // GenerateOption is an option used with the Generate function
-type GenerateOption func(*generateOptions)
+type GenerateOption func(*GenerateOptions)
 
 // GenerateEnumAsSumType causes enums to be be rendered as sum types
-func GenerateEnumAsSumType(opt *generateOptions) {
-	opt.enumsAsSumTypes = true
+func GenerateEnumAsSumType(opt *GenerateOptions) {
+	opt.EnumsAsSumTypes = true
+}
+
+// GenerateESModule ensures the output is a plain ES module. It only validates the options: without a namespace the
+// output consists of top-level exports anyway, and this option makes Render fail if a namespace is set, e.g. by options
+// shared with other calls. Use RenderModules with GenerateModuleFacades to group the types of a package instead.
+func GenerateESModule(opt *GenerateOptions) {
+	opt.ESModule = true
+}
+
+// GenerateDeclarations produces declaration-only code suitable for a .d.ts file. Enums are
+// rendered as `declare enum`, or as sum types if GenerateEnumAsSumType is set. It cannot be combined
+// with options which produce implementations, i.e. classes, type guards, JSON-RPC clients and mocks.
+func GenerateDeclarations(opt *GenerateOptions) {
+	opt.Declarations = true
+}
+
+// GenerateModuleFacades causes the index module produced by RenderModules to re-export
+// each module under its name (`export * as mypkg from "./mypkg"`) instead of flattening them.
+func GenerateModuleFacades(opt *GenerateOptions) {
+	opt.ModuleFacades = true
+}
+
+// GenerateTypeGuards produces a runtime type guard function (`isFoo(v: unknown): v is Foo`)
+// for every interface and enum. The guards check member presence, primitive types, array
+// elements, map values and enum membership, and need no runtime dependencies.
+func GenerateTypeGuards(opt *GenerateOptions) {
+	opt.TypeGuards = true
+}
+
+// GenerateClasses produces a class rather than an interface for every struct. Besides typed
+// fields each class has a static fromJSON method which recursively constructs nested classes
+// and converts mapped types (e.g. RFC3339 strings to Date), and a toJSON method inverting it.
+func GenerateClasses(opt *GenerateOptions) {
+	opt.Classes = true
+}
+
+// GenerateJSONRPCClients produces a client class for every interface consisting of methods.
+// The client implements the interface by making JSON-RPC 2.0 calls over a pluggable transport.
+// To this end the methods of such interfaces return promises.
+func GenerateJSONRPCClients(cfg ...JSONRPCOption) GenerateOption {
+	return func(opt *GenerateOptions) {
+		jsonrpc := newJSONRPCOptions(cfg)
+		opt.JSONRPC = &jsonrpc
+	}
+}
+
+// GenerateMocks produces a mock class for every interface consisting of methods, e.g. `FooMock implements Foo`.
+// Mocks record their calls and answer them using configurable return values or handlers.
+func GenerateMocks(opt *GenerateOptions) {
+	opt.Mocks = true
+}
+
+// GenerateJSON produces JSON rather than YAML for renderers which support both
+func GenerateJSON(opt *GenerateOptions) {
+	opt.JSON = true
 }
 
 // GenerateOutputTo sets the writer to which we'll write the generated TS code
 func GenerateOutputTo(out io.Writer) GenerateOption {
-	return func(opt *generateOptions) {
-		opt.out = out
+	return func(opt *GenerateOptions) {
+		opt.Out = out
+	}
+}
+
+// GenerateUsing produces output using a custom renderer rather than the default Typescript one
+func GenerateUsing(r Renderer) GenerateOption {
+	return func(opt *GenerateOptions) {
+		opt.Renderer = r
+	}
+}
+
+// GenerateTemplate overrides a named sub-template of the TemplateRenderer, e.g. "iface", "root-enum" or "comment",
+// and selects that renderer. The template has access to the same functions as the built-in ones, and can use the other sub-templates.
+// Like GenerateUsing it replaces the renderer set by earlier options, i.e. combined with GenerateUsing the last option wins.
+func GenerateTemplate(name, tpl string) GenerateOption {
+	return func(opt *GenerateOptions) {
+		opt.Renderer = TemplateRenderer
+		if opt.Templates == nil {
+			opt.Templates = make(map[string]string)
+		}
+		opt.Templates[name] = tpl
 	}
 }
 
 // GenerateNamespace produces a namespace in which the generated types live
 func GenerateNamespace(ns string) GenerateOption {
-	return func(opt *generateOptions) {
+	return func(opt *GenerateOptions) {
 		opt.Namespace = ns
 	}
 }
 
 // GenerateAdditionalPreamble produces additional output at the beginning of the Typescript code
 func GenerateAdditionalPreamble(preamble string) GenerateOption {
-	return func(opt *generateOptions) {
+	return func(opt *GenerateOptions) {
 		opt.Preamble += preamble
 	}
 }
 
 // GeneratePreamble produces output at the beginning of the Typescript code
 func GeneratePreamble(preamble string) GenerateOption {
-	return func(opt *generateOptions) {
+	return func(opt *GenerateOptions) {
 		opt.Preamble = preamble
 	}
 }
 
-// Render produces TypeScript code
-func Render(types []TypescriptType, cfg ...GenerateOption) error {
-	opts := generateOptions{
-		out:      os.Stdout,
+func newGenerateOptions(cfg []GenerateOption) GenerateOptions {
+	opts := GenerateOptions{
+		Out:      os.Stdout,
+		Renderer: TypescriptRenderer,
+		Format:   DefaultFormat,
 		Preamble: fmt.Sprintf("// generated using github.com/32leaves/bel on %s\n// DO NOT MODIFY\n", time.Now()),
 	}
 	for _, c := range cfg {
 		c(&opts)
 	}
+	return opts
+}
+
+// Render produces TypeScript code, or the output of the renderer configured using GenerateUsing
+func Render(types []TypescriptType, cfg ...GenerateOption) error {
+	opts := newGenerateOptions(cfg)
+	return opts.Renderer.Render(types, opts)
+}
+
+// knownTypes returns the types the rendered types may refer to
+func (opts GenerateOptions) knownTypes(types []TypescriptType) []TypescriptType {
+	if opts.allTypes != nil {
+		return opts.allTypes
+	}
+	return types
+}
+
+// validateGenerateOptions checks for options which cannot be combined
+func validateGenerateOptions(opts GenerateOptions) error {
+	if opts.ESModule && opts.Namespace != "" {
+		return fmt.Errorf("namespaces are not supported in ES module mode")
+	}
+	if opts.Declarations {
+		// declarations cannot contain the implementations these options produce
+		switch {
+		case opts.Classes:
+			return fmt.Errorf("classes are not supported in declaration mode")
+		case opts.TypeGuards:
+			return fmt.Errorf("type guards are not supported in declaration mode")
+		case opts.JSONRPC != nil:
+			return fmt.Errorf("JSON-RPC clients are not supported in declaration mode")
+		case opts.Mocks:
+			return fmt.Errorf("mocks are not supported in declaration mode")
+		}
+	}
+	return nil
+}
+
+// renderTemplate produces TypeScript code using interfaceTemplate and the sub-template overrides
+func renderTemplate(types []TypescriptType, opts GenerateOptions) error {
+	if err := validateGenerateOptions(opts); err != nil {
+		return err
+	}
 
 	getParam := func(nme string, idx, minlen int) func(t TypescriptType) (*TypescriptType, error) {
 		return func(t TypescriptType) (*TypescriptType, error) {
@@ -132,18 +279,89 @@ func Render(types []TypescriptType, cfg ...GenerateOption) error {
 		}
 	}
 
+	guards := newGuardRenderer(opts.knownTypes(types))
+	classes := newClassRenderer(opts.knownTypes(types))
+	var clients *jsonrpcClientRenderer
+	if opts.JSONRPC != nil {
+		clients = &jsonrpcClientRenderer{opts: *opts.JSONRPC}
+	}
+
+	mocks := &mockRenderer{promises: clients != nil}
+
 	funcs := template.FuncMap{
 		"mapKeyType": getParam("map", 0, 2),
 		"mapValType": getParam("map", 1, 2),
 		"arrType":    getParam("array", 0, 1),
 		"subt":       executeTpl(nil),
 		"subtroot": executeTpl(func(t TypescriptType) string {
-			if t.Kind == TypescriptEnumKind && opts.enumsAsSumTypes {
+			if t.Kind == TypescriptEnumKind && opts.EnumsAsSumTypes {
 				return "root-st-" + string(t.Kind)
 			}
 
 			return "root-" + string(t.Kind)
 		}),
+		"guard": func(t TypescriptType) string {
+			if !opts.TypeGuards {
+				return ""
+			}
+			return guards.Guard(t)
+		},
+		"isClass": func(t TypescriptType) bool {
+			return opts.Classes && isClass(t)
+		},
+		"class": classes.Class,
+		"jsonrpcRuntime": func() string {
+			if clients == nil || opts.externalRuntime {
+				return ""
+			}
+			return jsonrpcRuntime
+		},
+		"client": func(t TypescriptType) string {
+			if clients == nil {
+				return ""
+			}
+			return clients.Client(t)
+		},
+		"mockRuntime": func() string {
+			if !opts.Mocks || opts.externalRuntime {
+				return ""
+			}
+			return mockRuntime
+		},
+		"mock": func(t TypescriptType) string {
+			if !opts.Mocks {
+				return ""
+			}
+			return mocks.Mock(t)
+		},
+		"promise": func(isFunction bool, t string) string {
+			if clients == nil || !isFunction {
+				return t
+			}
+			return "Promise<" + t + ">"
+		},
+		"declare": func() string {
+			// declarations within a declared namespace are ambient already
+			if opts.Declarations && opts.Namespace == "" {
+				return "declare "
+			}
+			return ""
+		},
+		"declareNamespace": func() string {
+			if opts.Declarations {
+				return "declare "
+			}
+			return ""
+		},
+		"terminator": func() string {
+			return opts.Format.MemberTerminator
+		},
+		"enumSeparator": func(idx, count int) string {
+			if idx == count-1 && !opts.Format.TrailingCommas {
+				return ""
+			}
+			return ","
+		},
 		"default": func(def, val string) string {
 			if val == "" {
 				return def
@@ -157,31 +375,20 @@ func Render(types []TypescriptType, cfg ...GenerateOption) error {
 	if err != nil {
 		return err
 	}
+	for name, override := range opts.Templates {
+		if tpl.Lookup(name) == nil {
+			return fmt.Errorf("cannot override unknown template %s", name)
+		}
+		if _, err := tpl.New(name).Parse(override); err != nil {
+			return fmt.Errorf("cannot parse template %s: %v", name, err)
+		}
+	}
 
 	opts.Types = types
 
-	r, w := io.Pipe()
-	scanner := bufio.NewScanner(r)
-	go func() {
-		emptylines := 0
-		for scanner.Scan() {
-			line := scanner.Text()
-			trimline := strings.TrimSpace(line)
-
-			if trimline != "" {
-				if emptylines > 1 {
-					fmt.Fprintln(opts.out)
-				}
-				fmt.Fprintln(opts.out, line)
-			}
-
-			if trimline == "" {
-				emptylines++
-			} else {
-				emptylines = 0
-			}
-		}
-	}()
-
-	return tpl.Execute(w, opts)
+	w := newFormattingWriter(opts.Out, opts.Format)
+	if err := tpl.Execute(w, opts); err != nil {
+		return err
+	}
+	return w.Flush()
 }
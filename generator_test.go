@@ -1,6 +1,11 @@
 package bel
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -9,7 +14,7 @@ type DemoService interface {
 }
 
 func TestGenerateStuff(t *testing.T) {
-	handler, err := NewParsedSourceEnumHandler(".")
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
 	if err != nil {
 		t.Error(err)
 		return
@@ -30,3 +35,277 @@ func TestGenerateStuff(t *testing.T) {
 		return
 	}
 }
+
+func TestESModuleRejectsNamespace(t *testing.T) {
+	err := Render(nil, GenerateESModule, GenerateNamespace("foobar"), GenerateOutputTo(ioutil.Discard))
+	if err == nil {
+		t.Error("expected an error when using a namespace in ES module mode")
+	}
+}
+
+func TestESModuleKeepsOutput(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// ES module mode only validates the options, as the output consists of top-level exports already
+	var plain, esm bytes.Buffer
+	err = Render(extract, GeneratePreamble(""), GenerateOutputTo(&plain))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = Render(extract, GenerateESModule, GeneratePreamble(""), GenerateOutputTo(&esm))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if esm.String() != plain.String() {
+		t.Errorf("ES module output differs:\n%s\n%s", esm.String(), plain.String())
+	}
+	if !strings.HasPrefix(esm.String(), "export interface DemoService {") {
+		t.Errorf("unexpected ES module output:\n%s", esm.String())
+	}
+}
+
+func TestGenerateDeclarations(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(StructWithEnum{}, WithEnumerations(handler), FollowStructs, SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Options     []GenerateOption
+		Expectation string
+	}{
+		{
+			"plain",
+			nil,
+			`export declare enum MyEnum {
+    MemberOne = "member-one",
+    MemberTwo = "member-two",
+    MemberThree = "member-three",
+}
+
+export declare enum MyOtherEnum {
+    OtherEnumOne = 0,
+    OtherEnumTwo = 1,
+    OtherEnumThree = 2,
+    OtherEnumFour = 3,
+}
+
+export interface StructWithEnum {
+    Bar: MyOtherEnum
+    Baz: string
+    Foo: MyEnum
+}
+`,
+		},
+		{
+			"namespace",
+			[]GenerateOption{GenerateNamespace("api"), GenerateEnumAsSumType},
+			`export declare namespace api {
+    export type MyEnum =
+        "member-one" | "member-two" | "member-three";
+
+    export type MyOtherEnum =
+        0 | 1 | 2 | 3;
+
+    export interface StructWithEnum {
+        Bar: MyOtherEnum
+        Baz: string
+        Foo: MyEnum
+    }
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		for i, r := range []Renderer{TypescriptRenderer, TemplateRenderer} {
+			var out bytes.Buffer
+			err := Render(extract, append(test.Options, GenerateDeclarations, GenerateUsing(r), GeneratePreamble(""), GenerateOutputTo(&out))...)
+			if err != nil {
+				t.Errorf("%s: %v", test.Name, err)
+				continue
+			}
+			act, exp := out.String(), test.Expectation
+			if i > 0 {
+				// the template renderer differs in whitespace only
+				act, exp = strings.Join(strings.Fields(act), " "), strings.Join(strings.Fields(exp), " ")
+			}
+			if act != exp {
+				t.Errorf("%s: unexpected output:\n%s", test.Name, out.String())
+			}
+		}
+	}
+
+	rejected := []struct {
+		Name   string
+		Option GenerateOption
+	}{
+		{"classes", GenerateClasses},
+		{"type guards", GenerateTypeGuards},
+		{"json-rpc clients", GenerateJSONRPCClients()},
+		{"mocks", GenerateMocks},
+	}
+	for _, test := range rejected {
+		t.Run(test.Name, func(t *testing.T) {
+			for _, r := range []Renderer{TypescriptRenderer, TemplateRenderer} {
+				err := Render(extract, GenerateDeclarations, test.Option, GenerateUsing(r), GenerateOutputTo(ioutil.Discard))
+				if err == nil {
+					t.Errorf("expected an error when combining declarations with %s", test.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateUsing(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var (
+		names []string
+		ns    string
+	)
+	renderer := RendererFunc(func(types []TypescriptType, opts GenerateOptions) error {
+		for _, t := range types {
+			names = append(names, t.Name)
+		}
+		ns = opts.Namespace
+		return nil
+	})
+	err = Render(extract, GenerateUsing(renderer), GenerateNamespace("foobar"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(names) != 1 || names[0] != "DemoService" {
+		t.Errorf("unexpected types passed to renderer: %v", names)
+	}
+	if ns != "foobar" {
+		t.Errorf("unexpected namespace passed to renderer: %s", ns)
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Tpl         string
+		Valid       bool
+		Expectation string
+	}{
+		{"comment", "// {{ .Comment }}\n", true, ""},
+		{"root-iface", "type {{ .Name }} = unknown;\n", true, "type DemoService = unknown;"},
+		{"iface", "{{ unknownFunction }}", false, ""},
+		{"does-not-exist", "", false, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Render(extract, GenerateTemplate(test.Name, test.Tpl), GeneratePreamble(""), GenerateOutputTo(&out))
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+			if test.Expectation != "" && strings.TrimSpace(out.String()) != test.Expectation {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateReplacesRenderer(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	custom := RendererFunc(func(types []TypescriptType, opts GenerateOptions) error {
+		_, err := io.WriteString(opts.Out, "custom")
+		return err
+	})
+	tests := []struct {
+		Name        string
+		Opts        []GenerateOption
+		Expectation string
+	}{
+		{"template last", []GenerateOption{GenerateUsing(custom), GenerateTemplate("root-iface", "template")}, "template"},
+		{"renderer last", []GenerateOption{GenerateTemplate("root-iface", "template"), GenerateUsing(custom)}, "custom"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Render(extract, append(test.Opts, GeneratePreamble(""), GenerateOutputTo(&out))...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if act := strings.TrimSpace(out.String()); act != test.Expectation {
+				t.Errorf("unexpected output: %q", act)
+			}
+		})
+	}
+}
+
+func TestRenderWritesAllOutput(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// Render must have written everything by the time it returns
+	for i := 0; i < 100; i++ {
+		var out bytes.Buffer
+		err = Render(extract, GeneratePreamble(""), GenerateOutputTo(&out))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if exp := "export interface DemoService {\n    SayHello(arg0: string, arg1: string): string\n}\n"; out.String() != exp {
+			t.Errorf("unexpected output: %q", out.String())
+			return
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestRenderReturnsWriteErrors(t *testing.T) {
+	extract, err := Extract((*DemoService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = Render(extract, GenerateOutputTo(failingWriter{}))
+	if err == nil || err.Error() != "write failed" {
+		t.Errorf("expected write error, got %v", err)
+	}
+}
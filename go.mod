@@ -1,6 +1,6 @@
 module github.com/32leaves/bel
 
-go 1.12
+go 1.16
 
 require (
 	github.com/alecthomas/repr v0.0.0-20181024024818-d37bc2a10ba1
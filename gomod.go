@@ -0,0 +1,122 @@
+package bel
+
+import (
+	"bufio"
+	"go/build"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// importPathResolver resolves directories to the import path of the Go package they contain,
+// using the go.mod of the enclosing module or, failing that, the GOPATH
+type importPathResolver struct {
+	// modules caches the module path declared by the go.mod in a directory, "" if there's none
+	modules map[string]string
+}
+
+func newImportPathResolver() *importPathResolver {
+	return &importPathResolver{modules: make(map[string]string)}
+}
+
+// ImportPath returns the import path of the package in dir, and false if it cannot be determined
+func (r *importPathResolver) ImportPath(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for root := dir; ; {
+		if mod := r.module(root); mod != "" {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", false
+			}
+			return joinImportPath(mod, filepath.ToSlash(rel)), true
+		}
+
+		parent := filepath.Dir(root)
+		if parent == root {
+			break
+		}
+		root = parent
+	}
+
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		src := filepath.Join(gopath, "src") + string(filepath.Separator)
+		if strings.HasPrefix(dir, src) {
+			return filepath.ToSlash(strings.TrimPrefix(dir, src)), true
+		}
+	}
+	return "", false
+}
+
+// module returns the module path declared by the go.mod file in dir, if there is one
+func (r *importPathResolver) module(dir string) string {
+	if mod, ok := r.modules[dir]; ok {
+		return mod
+	}
+	mod := readModulePath(filepath.Join(dir, "go.mod"))
+	r.modules[dir] = mod
+	return mod
+}
+
+// fsImportPath returns the import path of the package in dir using the go.mod files in fsys, and false if there's none
+func fsImportPath(fsys fs.FS, dir string) (string, bool) {
+	for root := dir; ; root = path.Dir(root) {
+		f, err := fsys.Open(path.Join(root, "go.mod"))
+		if err == nil {
+			mod := parseModulePath(f)
+			f.Close()
+			if mod != "" {
+				return joinImportPath(mod, strings.TrimPrefix(strings.TrimPrefix(dir, root), "/")), true
+			}
+		}
+		if root == "." || root == "/" {
+			return "", false
+		}
+	}
+}
+
+// joinImportPath appends a slash-separated relative path to an import path
+func joinImportPath(importPath, rel string) string {
+	if rel == "" || rel == "." {
+		return importPath
+	}
+	return importPath + "/" + rel
+}
+
+// readModulePath returns the path of the module directive in a go.mod file, or "" if there's none
+func readModulePath(fn string) string {
+	f, err := os.Open(fn)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	return parseModulePath(f)
+}
+
+// parseModulePath returns the path of the module directive of go.mod content, or "" if there's none
+func parseModulePath(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+		mod := fields[1]
+		if unquoted, err := strconv.Unquote(mod); err == nil {
+			mod = unquoted
+		}
+		return mod
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package bel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// guardRenderer produces runtime type guard functions
+type guardRenderer struct {
+	// guarded contains the types for which we produce a guard
+	guarded map[string]bool
+}
+
+func newGuardRenderer(types []TypescriptType) *guardRenderer {
+	guarded := make(map[string]bool)
+	for _, t := range types {
+		if hasGuard(t) {
+			guarded[t.Name] = true
+		}
+	}
+	return &guardRenderer{guarded: guarded}
+}
+
+// hasGuard returns true if we produce a guard for t
+func hasGuard(t TypescriptType) bool {
+	return t.Kind == TypescriptInterfaceKind || t.Kind == TypescriptEnumKind || t.Kind == TypescriptUnionKind
+}
+
+// guardName is the name of the type guard function for a type
+func guardName(name string) string {
+	return "is" + name
+}
+
+// Guard produces a type guard function (`isFoo(v: unknown): v is Foo`) for interfaces, enums and unions
+func (g *guardRenderer) Guard(t TypescriptType) string {
+	var body string
+	switch t.Kind {
+	case TypescriptInterfaceKind, TypescriptUnionKind:
+		body = g.check("v", t, 0)
+	case TypescriptEnumKind:
+		body = enumCheck("v", t.EnumMembers)
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("export function %s(v: unknown): v is %s {\n    return %s;\n}\n", guardName(t.Name), t.Name, body)
+}
+
+// check produces an expression which tests if expr is of type t. depth is used to produce unique variable names.
+func (g *guardRenderer) check(expr string, t TypescriptType, depth int) string {
+	res := g.nonNullCheck(expr, t, depth)
+	if t.IsNullable {
+		res = fmt.Sprintf("(%s === null || %s)", expr, res)
+	}
+	return res
+}
+
+func (g *guardRenderer) nonNullCheck(expr string, t TypescriptType, depth int) string {
+	switch t.Kind {
+	case TypescriptSimpleKind:
+		switch t.Name {
+		case "string", "number", "boolean":
+			return fmt.Sprintf("typeof %s === %q", expr, t.Name)
+		}
+		if g.guarded[t.Name] {
+			return fmt.Sprintf("%s(%s)", guardName(t.Name), expr)
+		}
+		// we know nothing about this type, hence can only check for its presence
+		return fmt.Sprintf("%s !== undefined", expr)
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return "false"
+		}
+		elem := fmt.Sprintf("e%d", depth)
+		return fmt.Sprintf("Array.isArray(%s) && (%s as unknown[]).every((%s: unknown) => %s)", expr, expr, elem, g.check(elem, t.Params[0], depth+1))
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return "false"
+		}
+		elem := fmt.Sprintf("e%d", depth)
+		return fmt.Sprintf("typeof %s === \"object\" && %s !== null && !Array.isArray(%s) && Object.values(%s as object).every((%s: unknown) => %s)",
+			expr, expr, expr, expr, elem, g.check(elem, t.Params[1], depth+1))
+	case TypescriptInterfaceKind:
+		checks := []string{fmt.Sprintf("typeof %s === \"object\"", expr), fmt.Sprintf("%s !== null", expr)}
+		for _, m := range t.Members {
+			member := fmt.Sprintf("(%s as { [key: string]: unknown })[%s]", expr, strconv.Quote(m.Name))
+
+			var c string
+			if m.IsFunction {
+				c = fmt.Sprintf("typeof %s === \"function\"", member)
+			} else {
+				c = g.check(member, m.Type, depth)
+			}
+			if m.IsOptional {
+				c = fmt.Sprintf("(%s === undefined || %s)", member, c)
+			}
+			checks = append(checks, c)
+		}
+		return strings.Join(checks, "\n        && ")
+	case TypescriptUnionKind:
+		if len(t.Params) == 0 {
+			return "false"
+		}
+		checks := make([]string, len(t.Params))
+		for i, p := range t.Params {
+			checks[i] = g.check(expr, p, depth)
+		}
+		return "(" + strings.Join(checks, " || ") + ")"
+	case TypescriptLiteralKind:
+		return fmt.Sprintf("%s === %s", expr, t.Name)
+	}
+	return "false"
+}
+
+// enumCheck produces an expression which tests if expr is one of the enum's values
+func enumCheck(expr string, members []TypescriptEnumMember) string {
+	if len(members) == 0 {
+		return "false"
+	}
+
+	checks := make([]string, len(members))
+	for i, m := range members {
+		checks[i] = fmt.Sprintf("%s === %s", expr, m.Value)
+	}
+	return strings.Join(checks, " || ")
+}
@@ -0,0 +1,50 @@
+package bel
+
+import (
+	"testing"
+)
+
+// GuardedStruct is checked by a type guard
+type GuardedStruct struct {
+	Name     string              `json:"name"`
+	Kind     MyEnum              `json:"kind,omitempty"`
+	Tags     []string            `json:"tags"`
+	Lookup   map[string]*float64 `json:"lookup"`
+	Contains AnotherTestStruct   `json:"contains"`
+}
+
+func TestTypeGuards(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(GuardedStruct{}, WithEnumerations(handler), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	guards := newGuardRenderer(extract)
+	expectation := map[string]string{
+		"GuardedStruct": `export function isGuardedStruct(v: unknown): v is GuardedStruct {
+    return typeof v === "object"
+        && v !== null
+        && (v as { [key: string]: unknown })["contains"] !== undefined
+        && ((v as { [key: string]: unknown })["kind"] === undefined || isMyEnum((v as { [key: string]: unknown })["kind"]))
+        && typeof (v as { [key: string]: unknown })["lookup"] === "object" && (v as { [key: string]: unknown })["lookup"] !== null && !Array.isArray((v as { [key: string]: unknown })["lookup"]) && Object.values((v as { [key: string]: unknown })["lookup"] as object).every((e0: unknown) => (e0 === null || typeof e0 === "number"))
+        && typeof (v as { [key: string]: unknown })["name"] === "string"
+        && Array.isArray((v as { [key: string]: unknown })["tags"]) && ((v as { [key: string]: unknown })["tags"] as unknown[]).every((e0: unknown) => typeof e0 === "string");
+}
+`,
+		"MyEnum": `export function isMyEnum(v: unknown): v is MyEnum {
+    return v === "member-one" || v === "member-two" || v === "member-three";
+}
+`,
+	}
+	for _, tpe := range extract {
+		if act := guards.Guard(tpe); act != expectation[tpe.Name] {
+			t.Errorf("unexpected guard for %s:\n%s", tpe.Name, act)
+		}
+	}
+}
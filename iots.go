@@ -0,0 +1,208 @@
+package bel
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ioTsRenderer produces io-ts codecs for a set of types
+type ioTsRenderer struct {
+	known     map[string]bool
+	recursive map[string]bool
+}
+
+// RenderIoTs produces an io-ts codec (`export const Foo = t.type({...})`) and its static
+// type (`export type Foo = t.TypeOf<typeof Foo>`) for each of the types. Codecs use the same
+// names and dependency order as the other renderers, and recursive types are declared
+// using t.recursion and an explicit type.
+func RenderIoTs(types []TypescriptType, cfg ...GenerateOption) error {
+	return renderIoTs(types, newGenerateOptions(cfg))
+}
+
+func renderIoTs(types []TypescriptType, opts GenerateOptions) error {
+	ordered, recursive := dependencyOrder(types)
+	r := &ioTsRenderer{
+		known:     make(map[string]bool),
+		recursive: recursive,
+	}
+	for _, t := range types {
+		r.known[t.Name] = true
+	}
+
+	var out strings.Builder
+	out.WriteString(opts.Preamble)
+	out.WriteString("import * as t from \"io-ts\";\n")
+	for _, t := range ordered {
+		if isServiceInterface(t) {
+			continue
+		}
+
+		decl, err := r.declaration(t)
+		if err != nil {
+			return fmt.Errorf("cannot produce io-ts codec for %s: %v", t.Name, err)
+		}
+		out.WriteString("\n")
+		out.WriteString(decl)
+	}
+
+	_, err := io.WriteString(opts.Out, out.String())
+	return err
+}
+
+func (r *ioTsRenderer) declaration(t TypescriptType) (string, error) {
+	codec, err := r.codec(t)
+	if err != nil {
+		return "", err
+	}
+
+	var res strings.Builder
+	res.WriteString(docComment(t.Comment, 0))
+	if r.recursive[t.Name] {
+		// io-ts cannot infer the type of recursive codecs
+		fmt.Fprintf(&res, "export type %s = %s;\n", t.Name, typeExpr(declaredType(t)))
+		fmt.Fprintf(&res, "export const %s: t.Type<%s> = t.recursion(%q, () => %s);\n", t.Name, t.Name, t.Name, codec)
+		return res.String(), nil
+	}
+
+	fmt.Fprintf(&res, "export const %s = %s;\n", t.Name, codec)
+	fmt.Fprintf(&res, "export type %s = t.TypeOf<typeof %s>;\n", t.Name, t.Name)
+	return res.String(), nil
+}
+
+func (r *ioTsRenderer) codec(t TypescriptType) (string, error) {
+	res, err := r.nonNullCodec(t)
+	if err != nil {
+		return "", err
+	}
+	if t.IsNullable {
+		res = fmt.Sprintf("t.union([%s, t.null])", res)
+	}
+	return res, nil
+}
+
+func (r *ioTsRenderer) nonNullCodec(t TypescriptType) (string, error) {
+	switch t.Kind {
+	case TypescriptSimpleKind:
+		switch t.Name {
+		case "string", "number", "boolean":
+			return "t." + t.Name, nil
+		}
+		if !r.known[t.Name] {
+			return "", fmt.Errorf("unknown type %s - consider extracting with FollowStructs", t.Name)
+		}
+		return t.Name, nil
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return "", fmt.Errorf("array needs 1 type param")
+		}
+		elem, err := r.codec(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("t.array(%s)", elem), nil
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return "", fmt.Errorf("map needs 2 type params")
+		}
+		// JSON object keys are always strings, unless they're constrained by an enum
+		key := "t.string"
+		if k := t.Params[0]; k.Kind == TypescriptSimpleKind && k.Name != "string" && k.Name != "number" {
+			var err error
+			if key, err = r.codec(k); err != nil {
+				return "", err
+			}
+		}
+		val, err := r.codec(t.Params[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("t.record(%s, %s)", key, val), nil
+	case TypescriptEnumKind:
+		return ioTsEnum(t.EnumMembers), nil
+	case TypescriptLiteralKind:
+		return fmt.Sprintf("t.literal(%s)", t.Name), nil
+	case TypescriptUnionKind:
+		variants := make([]string, len(t.Params))
+		for i, p := range t.Params {
+			c, err := r.codec(p)
+			if err != nil {
+				return "", err
+			}
+			variants[i] = c
+		}
+		switch len(variants) {
+		case 0:
+			return "t.never", nil
+		case 1:
+			return variants[0], nil
+		}
+		return fmt.Sprintf("t.union([%s])", strings.Join(variants, ", ")), nil
+	case TypescriptInterfaceKind:
+		var required, optional []string
+		for _, m := range t.Members {
+			if m.IsFunction {
+				continue
+			}
+
+			c, err := r.codec(m.Type)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", m.Name, err)
+			}
+			prop := fmt.Sprintf("%s: %s,", propertyName(m.Name), c)
+			if m.IsOptional {
+				optional = append(optional, prop)
+			} else {
+				required = append(required, prop)
+			}
+		}
+
+		props := func(fn string, members []string) string {
+			if len(members) == 0 {
+				return fn + "({})"
+			}
+			return fmt.Sprintf("%s({\n    %s\n})", fn, strings.Replace(strings.Join(members, "\n"), "\n", "\n    ", -1))
+		}
+		switch {
+		case len(optional) == 0:
+			return props("t.type", required), nil
+		case len(required) == 0:
+			return props("t.partial", optional), nil
+		}
+		return fmt.Sprintf("t.intersection([%s, %s])", props("t.type", required), props("t.partial", optional)), nil
+	}
+	return "", fmt.Errorf("unsupported kind %s", t.Kind)
+}
+
+// ioTsEnum produces t.keyof for string enums, and a union of literals otherwise
+func ioTsEnum(members []TypescriptEnumMember) string {
+	values := make([]string, len(members))
+	allStrings := true
+	for i, m := range members {
+		values[i] = m.Value
+		if _, err := strconv.Unquote(m.Value); err != nil {
+			allStrings = false
+		}
+	}
+
+	if len(values) == 0 {
+		return "t.never"
+	}
+	if allStrings {
+		keys := make([]string, len(values))
+		for i, v := range values {
+			s, _ := strconv.Unquote(v)
+			keys[i] = fmt.Sprintf("%s: null", strconv.Quote(s))
+		}
+		return fmt.Sprintf("t.keyof({ %s })", strings.Join(keys, ", "))
+	}
+	if len(values) == 1 {
+		return fmt.Sprintf("t.literal(%s)", values[0])
+	}
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = fmt.Sprintf("t.literal(%s)", v)
+	}
+	return fmt.Sprintf("t.union([%s])", strings.Join(literals, ", "))
+}
@@ -0,0 +1,79 @@
+package bel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TreeNode is a recursive type
+type TreeNode struct {
+	Label    MyEnum             `json:"label"`
+	Children []TreeNode         `json:"children,omitempty"`
+	Leaf     *AnotherTestStruct `json:"leaf"`
+	Weights  map[string]float64 `json:"weights"`
+}
+
+func TestRenderIoTs(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(TreeNode{}, FollowStructs, WithEnumerations(handler), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderIoTs(extract, GenerateOutputTo(&out), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := `import * as t from "io-ts";
+
+export const AnotherTestStruct = t.type({
+    Bar: t.boolean,
+    Foo: t.string,
+});
+export type AnotherTestStruct = t.TypeOf<typeof AnotherTestStruct>;
+
+export const MyEnum = t.keyof({ "member-one": null, "member-two": null, "member-three": null });
+export type MyEnum = t.TypeOf<typeof MyEnum>;
+
+export type TreeNode = { children?: TreeNode[]; label: MyEnum; leaf: AnotherTestStruct | null; weights: { [key: string]: number } };
+export const TreeNode: t.Type<TreeNode> = t.recursion("TreeNode", () => t.intersection([t.type({
+    label: MyEnum,
+    leaf: t.union([AnotherTestStruct, t.null]),
+    weights: t.record(t.string, t.number),
+}), t.partial({
+    children: t.array(TreeNode),
+})]));
+`
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected io-ts codecs:\n%s", act)
+	}
+}
+
+func TestRenderIoTsComments(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "Note", Kind: TypescriptInterfaceKind, Comment: "Note is documented\n\nacross several lines", Members: []TypescriptMember{
+			{TypedElement: TypedElement{Name: "text", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}},
+		}},
+	}
+
+	var out bytes.Buffer
+	err := RenderIoTs(types, GenerateOutputTo(&out), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := "/**\n * Note is documented\n *\n * across several lines\n */\nexport const Note = t.type({"
+	if act := out.String(); !strings.Contains(act, expectation) {
+		t.Errorf("unexpected io-ts codecs:\n%s", act)
+	}
+}
@@ -0,0 +1,168 @@
+package bel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// JSONRPCMethodNamer produces the JSON-RPC method name for a method of a service (interface)
+type JSONRPCMethodNamer func(service, method string) string
+
+// DottedMethodNames names JSON-RPC methods Service.Method - this is the default
+func DottedMethodNames(service, method string) string {
+	return service + "." + method
+}
+
+// PlainMethodNames names JSON-RPC methods after the method alone, disregarding the service
+func PlainMethodNames(service, method string) string {
+	return method
+}
+
+// JSONRPCOption configures JSON-RPC code generation
+type JSONRPCOption func(*JSONRPCOptions)
+
+// JSONRPCOptions configures the JSON-RPC clients and dispatchers. They must agree on the options to interoperate.
+type JSONRPCOptions struct {
+	MethodNamer  JSONRPCMethodNamer
+	ServiceNamer TypeNamer
+	NamedParams  bool
+}
+
+func newJSONRPCOptions(cfg []JSONRPCOption) JSONRPCOptions {
+	opts := JSONRPCOptions{
+		MethodNamer: DottedMethodNames,
+		ServiceNamer: func(t reflect.Type) string {
+			return strcase.ToCamel(t.Name())
+		},
+	}
+	for _, c := range cfg {
+		c(&opts)
+	}
+	return opts
+}
+
+// JSONRPCMethodNaming configures how JSON-RPC methods are named
+func JSONRPCMethodNaming(namer JSONRPCMethodNamer) JSONRPCOption {
+	return func(opts *JSONRPCOptions) {
+		opts.MethodNamer = namer
+	}
+}
+
+// JSONRPCServiceNaming configures how dispatchers name services. Clients use the names of the extracted interfaces,
+// hence dispatchers must use the same namer that was passed to CustomNamer when extracting the clients' types.
+func JSONRPCServiceNaming(namer TypeNamer) JSONRPCOption {
+	return func(opts *JSONRPCOptions) {
+		opts.ServiceNamer = namer
+	}
+}
+
+// JSONRPCNamedParams passes parameters by name (as object) rather than by position (as array)
+func JSONRPCNamedParams(opts *JSONRPCOptions) {
+	opts.NamedParams = true
+}
+
+// jsonrpcRuntime is the support code the generated clients need
+const jsonrpcRuntime = `/**
+ * JSONRPCTransport sends a JSON-RPC request and resolves to the result of the call.
+ * Transports reject with a JSONRPCError if the server responds with an error.
+ */
+export type JSONRPCTransport = (method: string, params: unknown[] | { [key: string]: unknown }) => Promise<unknown>;
+
+/**
+ * JSONRPCErrorObject is the error object of a JSON-RPC 2.0 response
+ */
+export interface JSONRPCErrorObject {
+    code: number;
+    message: string;
+    data?: unknown;
+}
+
+/**
+ * JSONRPCError is raised when a JSON-RPC call fails
+ */
+export class JSONRPCError extends Error implements JSONRPCErrorObject {
+    readonly code: number;
+    readonly data?: unknown;
+
+    constructor(err: JSONRPCErrorObject) {
+        super(err.message);
+        this.name = "JSONRPCError";
+        this.code = err.code;
+        this.data = err.data;
+    }
+}
+
+/**
+ * httpJSONRPCTransport sends JSON-RPC 2.0 requests to url using fetch
+ */
+export function httpJSONRPCTransport(url: string): JSONRPCTransport {
+    let id = 0;
+    return async (method, params) => {
+        const resp = await fetch(url, {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ jsonrpc: "2.0", id: ++id, method, params }),
+        });
+        const body = await resp.json() as { result?: unknown, error?: JSONRPCErrorObject };
+        if (body.error) {
+            throw new JSONRPCError(body.error);
+        }
+        return body.result;
+    };
+}
+`
+
+// jsonrpcClientRenderer produces JSON-RPC clients for service interfaces
+type jsonrpcClientRenderer struct {
+	opts JSONRPCOptions
+}
+
+// clientName is the name of the JSON-RPC client class for a service
+func clientName(service string) string {
+	return service + "Client"
+}
+
+// Client produces a class implementing a service interface by making JSON-RPC calls
+func (r *jsonrpcClientRenderer) Client(t TypescriptType) string {
+	if !isServiceInterface(t) {
+		return ""
+	}
+
+	var res strings.Builder
+	fmt.Fprintf(&res, "/**\n * %s implements %s using JSON-RPC\n */\n", clientName(t.Name), t.Name)
+	fmt.Fprintf(&res, "export class %s implements %s {\n", clientName(t.Name), t.Name)
+	res.WriteString("    constructor(private readonly transport: JSONRPCTransport) {}\n")
+	for _, m := range t.Members {
+		args := make([]string, len(m.Args))
+		params := make([]string, len(m.Args))
+		for i, a := range m.Args {
+			args[i] = fmt.Sprintf("%s: %s", a.Name, interfaceTypeExpr(a.Type))
+			params[i] = a.Name
+			if r.opts.NamedParams {
+				params[i] = fmt.Sprintf("%s: %s", propertyName(a.Name), a.Name)
+			}
+		}
+		paramList := "[" + strings.Join(params, ", ") + "]"
+		if r.opts.NamedParams {
+			paramList = "{ " + strings.Join(params, ", ") + " }"
+			if len(params) == 0 {
+				paramList = "{}"
+			}
+		}
+		call := fmt.Sprintf("this.transport(%q, %s)", r.opts.MethodNamer(t.Name, m.Name), paramList)
+
+		ret := interfaceTypeExpr(m.Type)
+		fmt.Fprintf(&res, "\n    async %s(%s): Promise<%s> {\n", m.Name, strings.Join(args, ", "), ret)
+		if ret == "void" {
+			fmt.Fprintf(&res, "        await %s;\n", call)
+		} else {
+			fmt.Fprintf(&res, "        return (await %s) as %s;\n", call, ret)
+		}
+		res.WriteString("    }\n")
+	}
+	res.WriteString("}\n")
+	return res.String()
+}
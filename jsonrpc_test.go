@@ -0,0 +1,134 @@
+package bel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type JSONRPCService interface {
+	SayHello(name, msg string) (string, error)
+	Ping() error
+	ListStructs(filter *AnotherTestStruct) ([]AnotherTestStruct, error)
+}
+
+// JSONRPCItemService returns a pointer, which the client and the interface must type alike
+type JSONRPCItemService interface {
+	Get(id string) (*AnotherTestStruct, error)
+}
+
+func TestJSONRPCClient(t *testing.T) {
+	extract, err := Extract((*JSONRPCService)(nil), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Opts        []JSONRPCOption
+		Expectation string
+	}{
+		{
+			"positional",
+			nil,
+			`/**
+ * JSONRPCServiceClient implements JSONRPCService using JSON-RPC
+ */
+export class JSONRPCServiceClient implements JSONRPCService {
+    constructor(private readonly transport: JSONRPCTransport) {}
+
+    async ListStructs(arg0: AnotherTestStruct): Promise<AnotherTestStruct[]> {
+        return (await this.transport("JSONRPCService.ListStructs", [arg0])) as AnotherTestStruct[];
+    }
+
+    async Ping(): Promise<void> {
+        await this.transport("JSONRPCService.Ping", []);
+    }
+
+    async SayHello(arg0: string, arg1: string): Promise<string> {
+        return (await this.transport("JSONRPCService.SayHello", [arg0, arg1])) as string;
+    }
+}
+`,
+		},
+		{
+			"named",
+			[]JSONRPCOption{JSONRPCNamedParams, JSONRPCMethodNaming(PlainMethodNames)},
+			`/**
+ * JSONRPCServiceClient implements JSONRPCService using JSON-RPC
+ */
+export class JSONRPCServiceClient implements JSONRPCService {
+    constructor(private readonly transport: JSONRPCTransport) {}
+
+    async ListStructs(arg0: AnotherTestStruct): Promise<AnotherTestStruct[]> {
+        return (await this.transport("ListStructs", { arg0: arg0 })) as AnotherTestStruct[];
+    }
+
+    async Ping(): Promise<void> {
+        await this.transport("Ping", {});
+    }
+
+    async SayHello(arg0: string, arg1: string): Promise<string> {
+        return (await this.transport("SayHello", { arg0: arg0, arg1: arg1 })) as string;
+    }
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := &jsonrpcClientRenderer{opts: newJSONRPCOptions(test.Opts)}
+			if act := r.Client(extract[0]); act != test.Expectation {
+				t.Errorf("unexpected client:\n%s", act)
+			}
+		})
+	}
+}
+
+func TestRenderJSONRPCClient(t *testing.T) {
+	extract, err := Extract((*JSONRPCItemService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := jsonrpcRuntime + `
+export interface JSONRPCItemService {
+    Get(arg0: string): Promise<AnotherTestStruct>
+}
+
+/**
+ * JSONRPCItemServiceClient implements JSONRPCItemService using JSON-RPC
+ */
+export class JSONRPCItemServiceClient implements JSONRPCItemService {
+    constructor(private readonly transport: JSONRPCTransport) {}
+
+    async Get(arg0: string): Promise<AnotherTestStruct> {
+        return (await this.transport("JSONRPCItemService.Get", [arg0])) as AnotherTestStruct;
+    }
+}
+`
+	var out bytes.Buffer
+	err = Render(extract, GenerateJSONRPCClients(), GeneratePreamble(""), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected output:\n%s", act)
+	}
+
+	// the template renderer must agree with the interface, too
+	out.Reset()
+	err = Render(extract, GenerateJSONRPCClients(), GenerateUsing(TemplateRenderer), GeneratePreamble(""), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, exp := range []string{"    Get(arg0: string): Promise<AnotherTestStruct>\n", "    async Get(arg0: string): Promise<AnotherTestStruct> {\n"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("template output does not contain %q:\n%s", exp, out.String())
+		}
+	}
+}
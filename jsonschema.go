@@ -0,0 +1,258 @@
+package bel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// jsonSchemaDraft is the JSON schema dialect we produce
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a (partial) JSON schema document. It doubles as OpenAPI schema object.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	AllOf                []*jsonSchema          `json:"allOf,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	OneOf                []*jsonSchema          `json:"oneOf,omitempty"`
+	Discriminator        *openAPIDiscriminator  `json:"discriminator,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	PropertyNames        *jsonSchema            `json:"propertyNames,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// openAPIDiscriminator names the property which distinguishes the variants of a oneOf schema
+type openAPIDiscriminator struct {
+	PropertyName string `json:"propertyName"`
+}
+
+// schemaBuilder translates Typescript types to JSON schema or OpenAPI schema objects
+type schemaBuilder struct {
+	defs      map[string]bool
+	refPrefix string
+	openAPI   bool
+}
+
+func newSchemaBuilder(types []TypescriptType, refPrefix string, openAPI bool) *schemaBuilder {
+	defs := make(map[string]bool)
+	for _, t := range types {
+		defs[t.Name] = true
+	}
+	return &schemaBuilder{defs: defs, refPrefix: refPrefix, openAPI: openAPI}
+}
+
+// RenderJSONSchema produces a JSON schema (draft 2020-12) document which contains
+// a definition for each named type. Methods of interfaces are not part of the
+// schema, and interfaces consisting only of methods are skipped altogether.
+func RenderJSONSchema(types []TypescriptType, cfg ...GenerateOption) error {
+	return renderJSONSchema(types, newGenerateOptions(cfg))
+}
+
+func renderJSONSchema(types []TypescriptType, opts GenerateOptions) error {
+	defs, err := newSchemaBuilder(types, "#/$defs/", false).definitions(types)
+	if err != nil {
+		return err
+	}
+	doc := &jsonSchema{
+		Schema: jsonSchemaDraft,
+		Defs:   defs,
+	}
+
+	enc := json.NewEncoder(opts.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// definitions produces a schema for each type which can be marshalled to JSON
+func (b *schemaBuilder) definitions(types []TypescriptType) (map[string]*jsonSchema, error) {
+	res := make(map[string]*jsonSchema)
+	for _, t := range types {
+		if isServiceInterface(t) {
+			continue
+		}
+
+		s, err := b.schemaFor(t)
+		if err != nil {
+			return nil, fmt.Errorf("cannot produce schema for %s: %v", t.Name, err)
+		}
+		res[t.Name] = s
+	}
+	return res, nil
+}
+
+// isServiceInterface returns true if t is an interface consisting only of methods
+func isServiceInterface(t TypescriptType) bool {
+	if t.Kind != TypescriptInterfaceKind || len(t.Members) == 0 {
+		return false
+	}
+	for _, m := range t.Members {
+		if !m.IsFunction {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *schemaBuilder) schemaFor(t TypescriptType) (*jsonSchema, error) {
+	res, err := b.nonNullSchemaFor(t)
+	if err != nil {
+		return nil, err
+	}
+	if !t.IsNullable {
+		return res, nil
+	}
+
+	if b.openAPI {
+		if res.Ref != "" {
+			// siblings of $ref are ignored in OpenAPI 3.0
+			return &jsonSchema{AllOf: []*jsonSchema{res}, Nullable: true}, nil
+		}
+		res.Nullable = true
+		return res, nil
+	}
+	if tpe, ok := res.Type.(string); ok {
+		res.Type = []string{tpe, "null"}
+		return res, nil
+	}
+	return &jsonSchema{AnyOf: []*jsonSchema{res, {Type: "null"}}}, nil
+}
+
+func (b *schemaBuilder) nonNullSchemaFor(t TypescriptType) (*jsonSchema, error) {
+	switch t.Kind {
+	case TypescriptSimpleKind:
+		switch t.Name {
+		case "string", "boolean":
+			return &jsonSchema{Type: t.Name, Format: t.Format}, nil
+		case "number":
+			if isIntegerFormat(t.Format) {
+				return &jsonSchema{Type: "integer", Format: t.Format}, nil
+			}
+			return &jsonSchema{Type: t.Name, Format: t.Format}, nil
+		}
+		if !b.defs[t.Name] {
+			return nil, fmt.Errorf("unknown type %s - consider extracting with FollowStructs", t.Name)
+		}
+		return &jsonSchema{Ref: b.refPrefix + t.Name}, nil
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return nil, fmt.Errorf("array needs 1 type param")
+		}
+		items, err := b.schemaFor(t.Params[0])
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return nil, fmt.Errorf("map needs 2 type params")
+		}
+		val, err := b.schemaFor(t.Params[1])
+		if err != nil {
+			return nil, err
+		}
+		res := &jsonSchema{Type: "object", AdditionalProperties: val}
+		if key := t.Params[0]; !b.openAPI && key.Kind == TypescriptSimpleKind && b.defs[key.Name] {
+			res.PropertyNames = &jsonSchema{Ref: b.refPrefix + key.Name}
+		}
+		return res, nil
+	case TypescriptLiteralKind:
+		res := &jsonSchema{Enum: []interface{}{jsonSchemaEnumValue(t.Name)}}
+		res.Type = jsonSchemaEnumType(res.Enum)
+		return res, nil
+	case TypescriptUnionKind:
+		res := &jsonSchema{Description: t.Comment}
+		for _, p := range t.Params {
+			s, err := b.schemaFor(p)
+			if err != nil {
+				return nil, err
+			}
+			res.OneOf = append(res.OneOf, s)
+		}
+		if b.openAPI && t.Discriminator != "" {
+			res.Discriminator = &openAPIDiscriminator{PropertyName: t.Discriminator}
+		}
+		return res, nil
+	case TypescriptEnumKind:
+		res := &jsonSchema{Description: t.Comment}
+		for _, m := range t.EnumMembers {
+			res.Enum = append(res.Enum, jsonSchemaEnumValue(m.Value))
+		}
+		res.Type = jsonSchemaEnumType(res.Enum)
+		return res, nil
+	case TypescriptInterfaceKind:
+		res := &jsonSchema{
+			Type:                 "object",
+			Description:          t.Comment,
+			Properties:           make(map[string]*jsonSchema),
+			AdditionalProperties: false,
+		}
+		for _, m := range t.Members {
+			if m.IsFunction {
+				continue
+			}
+
+			p, err := b.schemaFor(m.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", m.Name, err)
+			}
+			if m.Comment != "" {
+				p.Description = m.Comment
+			}
+			res.Properties[m.Name] = p
+			if !m.IsOptional {
+				res.Required = append(res.Required, m.Name)
+			}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("unsupported kind %s", t.Kind)
+}
+
+// jsonSchemaEnumValue converts the Go literal of an enum value to its JSON counterpart
+func jsonSchemaEnumValue(lit string) interface{} {
+	if s, err := strconv.Unquote(lit); err == nil {
+		return s
+	}
+	if _, err := strconv.ParseFloat(lit, 64); err == nil {
+		return json.Number(lit)
+	}
+	return lit
+}
+
+// jsonSchemaEnumType determines the type of enum values if they share one
+func jsonSchemaEnumType(values []interface{}) interface{} {
+	var res string
+	for _, v := range values {
+		var tpe string
+		switch val := v.(type) {
+		case string:
+			tpe = "string"
+		case json.Number:
+			tpe = "integer"
+			if _, err := val.Int64(); err != nil {
+				tpe = "number"
+			}
+		}
+
+		if res == "" || res == tpe {
+			res = tpe
+		} else if (res == "integer" && tpe == "number") || (res == "number" && tpe == "integer") {
+			res = "number"
+		} else {
+			return nil
+		}
+	}
+	if res == "" {
+		return nil
+	}
+	return res
+}
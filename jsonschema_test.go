@@ -0,0 +1,177 @@
+package bel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// StructWithEverything exercises all JSON schema constructs
+type StructWithEverything struct {
+	// Name is required
+	Name     string
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]int    `json:"labels"`
+	Kind     MyEnum            `json:"kind"`
+	Counts   map[MyEnum]uint32 `json:"counts,omitempty"`
+	Contains AnotherTestStruct
+}
+
+func TestRenderJSONSchema(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	docs, err := NewParsedSourceDocHandler(".", "github.com/32leaves/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(StructWithEverything{}, FollowStructs, WithEnumerations(handler), WithDocumentation(docs))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderJSONSchema(extract, GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var act map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &act); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var expectation map[string]interface{}
+	err = json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"AnotherTestStruct": {
+				"type": "object",
+				"description": "AnotherTestStruct is just yet another struct",
+				"properties": {
+					"Foo": {"type": "string", "description": "Foo has some documentation"},
+					"Bar": {"type": "boolean", "description": "Bar as well"}
+				},
+				"required": ["Foo", "Bar"],
+				"additionalProperties": false
+			},
+			"MyEnum": {
+				"type": "string",
+				"enum": ["member-one", "member-two", "member-three"]
+			},
+			"StructWithEverything": {
+				"type": "object",
+				"description": "StructWithEverything exercises all JSON schema constructs",
+				"properties": {
+					"Name": {"type": "string", "description": "Name is required"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"labels": {"type": "object", "additionalProperties": {"type": "integer", "format": "int64"}},
+					"kind": {"$ref": "#/$defs/MyEnum"},
+					"counts": {
+						"type": "object",
+						"propertyNames": {"$ref": "#/$defs/MyEnum"},
+						"additionalProperties": {"type": "integer", "format": "int64"}
+					},
+					"Contains": {"$ref": "#/$defs/AnotherTestStruct"}
+				},
+				"required": ["Name", "labels", "kind", "Contains"],
+				"additionalProperties": false
+			}
+		}
+	}`), &expectation)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	diff := deep.Equal(expectation, act)
+	for _, d := range diff {
+		t.Error(d)
+	}
+}
+
+func TestRenderJSONSchemaUnknownType(t *testing.T) {
+	extract, err := Extract(NestedStruct{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderJSONSchema(extract, GenerateOutputTo(&out))
+	if err == nil {
+		t.Error("expected an error for a reference to a type without definition")
+	}
+}
+
+// Counters has unsigned and signed integers as well as floats
+type Counters struct {
+	Hits   uint    `json:"hits"`
+	Total  uint64  `json:"total"`
+	Offset int32   `json:"offset"`
+	Ratio  float32 `json:"ratio"`
+}
+
+func TestRenderJSONSchemaIntegers(t *testing.T) {
+	extract, err := Extract(Counters{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name     string
+		Renderer Renderer
+	}{
+		{"JSON Schema", JSONSchemaRenderer},
+		{"OpenAPI", OpenAPIRenderer},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Render(extract, GenerateUsing(test.Renderer), GenerateJSON, GenerateOutputTo(&out))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			var act struct {
+				Defs       map[string]jsonSchema `json:"$defs"`
+				Components struct {
+					Schemas map[string]jsonSchema `json:"schemas"`
+				} `json:"components"`
+			}
+			if err := json.Unmarshal(out.Bytes(), &act); err != nil {
+				t.Error(err)
+				return
+			}
+			schema, ok := act.Defs["Counters"]
+			if !ok {
+				schema = act.Components.Schemas["Counters"]
+			}
+
+			expectation := map[string]string{
+				"hits":   "integer/uint64",
+				"total":  "integer/uint64",
+				"offset": "integer/int32",
+				"ratio":  "number/float",
+			}
+			props := make(map[string]string)
+			for name, p := range schema.Properties {
+				props[name] = fmt.Sprintf("%v/%s", p.Type, p.Format)
+			}
+			if diff := deep.Equal(props, expectation); diff != nil {
+				t.Errorf("%v: %s", diff, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package bel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mockRuntime is the support code the generated mocks need
+const mockRuntime = `/**
+ * ServiceMock records calls to a service and answers them using configured return values or handlers.
+ * Calls to methods for which there is neither fail.
+ */
+export class ServiceMock<T extends { [K in keyof T]: (...args: any[]) => any }> {
+    private recorded: { [K in keyof T]?: Parameters<T[K]>[] } = {};
+    private handlers: { [K in keyof T]?: T[K] } = {};
+
+    /**
+     * on configures a handler which answers all calls to method
+     */
+    on<K extends keyof T>(method: K, handler: T[K]): this {
+        this.handlers[method] = handler;
+        return this;
+    }
+
+    /**
+     * returns configures the value all calls to method return
+     */
+    returns<K extends keyof T>(method: K, value: ReturnType<T[K]>): this {
+        return this.on(method, ((..._args: unknown[]) => value) as unknown as T[K]);
+    }
+
+    /**
+     * calls returns the arguments of all calls to method
+     */
+    calls<K extends keyof T>(method: K): Parameters<T[K]>[] {
+        return this.recorded[method] || [];
+    }
+
+    /**
+     * assertCalled fails unless method was called exactly times times, or at least once if times is not given
+     */
+    assertCalled<K extends keyof T>(method: K, times?: number): void {
+        const n = this.calls(method).length;
+        if (times === undefined ? n === 0 : n !== times) {
+            const expected = times === undefined ? "at least once" : times + " times";
+            throw new Error("expected " + String(method) + " to be called " + expected + ", but it was called " + n + " times");
+        }
+    }
+
+    /**
+     * assertNotCalled fails if method was called
+     */
+    assertNotCalled<K extends keyof T>(method: K): void {
+        this.assertCalled(method, 0);
+    }
+
+    /**
+     * assertCalledWith fails unless method was called with args (compared by their JSON representation)
+     */
+    assertCalledWith<K extends keyof T>(method: K, ...args: Parameters<T[K]>): void {
+        const expected = JSON.stringify(args);
+        if (!this.calls(method).some(c => JSON.stringify(c) === expected)) {
+            throw new Error("expected " + String(method) + " to be called with " + expected);
+        }
+    }
+
+    /**
+     * reset forgets all recorded calls, return values and handlers
+     */
+    reset(): void {
+        this.recorded = {};
+        this.handlers = {};
+    }
+
+    protected invoke<K extends keyof T>(method: K, args: Parameters<T[K]>): ReturnType<T[K]> {
+        (this.recorded[method] = this.recorded[method] || []).push(args);
+
+        const handler = this.handlers[method];
+        if (handler === undefined) {
+            throw new Error("no return value or handler configured for " + String(method));
+        }
+        return (handler as (...args: unknown[]) => ReturnType<T[K]>)(...(args as unknown[]));
+    }
+}
+`
+
+// mockRenderer produces mock implementations of service interfaces
+type mockRenderer struct {
+	// promises is true if the methods of service interfaces return promises
+	promises bool
+}
+
+// mockName is the name of the mock class for a service
+func mockName(service string) string {
+	return service + "Mock"
+}
+
+// Mock produces a class implementing a service interface which records calls and answers them
+// using the return values and handlers configured in ServiceMock
+func (r *mockRenderer) Mock(t TypescriptType) string {
+	if !isServiceInterface(t) {
+		return ""
+	}
+
+	var res strings.Builder
+	fmt.Fprintf(&res, "/**\n * %s is a mock implementation of %s\n */\n", mockName(t.Name), t.Name)
+	fmt.Fprintf(&res, "export class %s extends ServiceMock<%s> implements %s {\n", mockName(t.Name), t.Name, t.Name)
+	for i, m := range t.Members {
+		args := make([]string, len(m.Args))
+		params := make([]string, len(m.Args))
+		for j, a := range m.Args {
+			args[j] = fmt.Sprintf("%s: %s", a.Name, interfaceTypeExpr(a.Type))
+			params[j] = a.Name
+		}
+
+		ret := interfaceTypeExpr(m.Type)
+		if r.promises {
+			ret = "Promise<" + ret + ">"
+		}
+		if i > 0 {
+			res.WriteString("\n")
+		}
+		fmt.Fprintf(&res, "    %s(%s): %s {\n", m.Name, strings.Join(args, ", "), ret)
+		fmt.Fprintf(&res, "        return this.invoke(%q, [%s]);\n", m.Name, strings.Join(params, ", "))
+		res.WriteString("    }\n")
+	}
+	res.WriteString("}\n")
+	return res.String()
+}
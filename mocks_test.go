@@ -0,0 +1,117 @@
+package bel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMock(t *testing.T) {
+	extract, err := Extract((*JSONRPCService)(nil), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Promises    bool
+		Expectation string
+	}{
+		{
+			"sync",
+			false,
+			`/**
+ * JSONRPCServiceMock is a mock implementation of JSONRPCService
+ */
+export class JSONRPCServiceMock extends ServiceMock<JSONRPCService> implements JSONRPCService {
+    ListStructs(arg0: AnotherTestStruct): AnotherTestStruct[] {
+        return this.invoke("ListStructs", [arg0]);
+    }
+
+    Ping(): void {
+        return this.invoke("Ping", []);
+    }
+
+    SayHello(arg0: string, arg1: string): string {
+        return this.invoke("SayHello", [arg0, arg1]);
+    }
+}
+`,
+		},
+		{
+			"promises",
+			true,
+			`/**
+ * JSONRPCServiceMock is a mock implementation of JSONRPCService
+ */
+export class JSONRPCServiceMock extends ServiceMock<JSONRPCService> implements JSONRPCService {
+    ListStructs(arg0: AnotherTestStruct): Promise<AnotherTestStruct[]> {
+        return this.invoke("ListStructs", [arg0]);
+    }
+
+    Ping(): Promise<void> {
+        return this.invoke("Ping", []);
+    }
+
+    SayHello(arg0: string, arg1: string): Promise<string> {
+        return this.invoke("SayHello", [arg0, arg1]);
+    }
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := &mockRenderer{promises: test.Promises}
+			if act := r.Mock(extract[0]); act != test.Expectation {
+				t.Errorf("unexpected mock:\n%s", act)
+			}
+		})
+	}
+}
+
+func TestRenderMocks(t *testing.T) {
+	extract, err := Extract((*JSONRPCItemService)(nil))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := mockRuntime + `
+export interface JSONRPCItemService {
+    Get(arg0: string): AnotherTestStruct
+}
+
+/**
+ * JSONRPCItemServiceMock is a mock implementation of JSONRPCItemService
+ */
+export class JSONRPCItemServiceMock extends ServiceMock<JSONRPCItemService> implements JSONRPCItemService {
+    Get(arg0: string): AnotherTestStruct {
+        return this.invoke("Get", [arg0]);
+    }
+}
+`
+	var out bytes.Buffer
+	err = Render(extract, GenerateMocks, GeneratePreamble(""), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected output:\n%s", act)
+	}
+
+	// the template renderer must agree with the interface, too
+	out.Reset()
+	err = Render(extract, GenerateMocks, GenerateUsing(TemplateRenderer), GeneratePreamble(""), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, exp := range []string{"    Get(arg0: string): AnotherTestStruct\n", "    Get(arg0: string): AnotherTestStruct {\n"} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("template output does not contain %q:\n%s", exp, out.String())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package bel
+
+import (
+	"fmt"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ModuleDocHandler provides Go doc documentation for the packages of the module in a directory, including nested
+// modules and dependencies. Unlike ParsedSourceDocHandler it needs no import paths: the package of a type is located
+// using the go.mod files in the module or, failing that, `go list`, and is indexed the first time it's needed.
+// Packages which cannot be found or parsed are undocumented.
+type ModuleDocHandler struct {
+	dir string
+
+	mu sync.Mutex
+	// modules are the directories of the modules in and around dir by module path, found on first use
+	modules map[string]string
+	// pkgs are the parsed packages by import path, nil if the package could not be indexed
+	pkgs map[string][]*doc.Package
+}
+
+// NewModuleDocHandler creates a new doc handler for the module in dir, or the module dir is part of
+func NewModuleDocHandler(dir string) (*ModuleDocHandler, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if stat, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !stat.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &ModuleDocHandler{dir: dir, pkgs: make(map[string][]*doc.Package)}, nil
+}
+
+// findModules returns the modules in dir, its subdirectories and the module enclosing dir, by module path
+func findModules(dir string) map[string]string {
+	res := make(map[string]string)
+
+	root := dir
+	for p := dir; ; {
+		if mod := readModulePath(filepath.Join(p, "go.mod")); mod != "" {
+			res[mod] = p
+			root = p
+			break
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			break
+		}
+		p = parent
+	}
+
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if p != root && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if mod := readModulePath(filepath.Join(p, "go.mod")); mod != "" {
+			res[mod] = p
+		}
+		return nil
+	})
+	return res
+}
+
+// packageDir finds the directory of a package: in the module with the longest matching path, or using `go list`
+func (h *ModuleDocHandler) packageDir(pkgPath string) (string, bool) {
+	if h.modules == nil {
+		h.modules = findModules(h.dir)
+	}
+
+	var mod string
+	for m := range h.modules {
+		if (pkgPath == m || strings.HasPrefix(pkgPath, m+"/")) && len(m) > len(mod) {
+			mod = m
+		}
+	}
+	if mod != "" {
+		rel := strings.TrimPrefix(strings.TrimPrefix(pkgPath, mod), "/")
+		dir := filepath.Join(h.modules[mod], filepath.FromSlash(rel))
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() && !h.inNestedModule(h.modules[mod], dir) {
+			return dir, true
+		}
+	}
+
+	// in module mode go/build asks the go command, which knows about dependencies and the module cache
+	p, err := build.Import(pkgPath, h.dir, build.FindOnly)
+	if err != nil {
+		return "", false
+	}
+	return p.Dir, true
+}
+
+// inNestedModule returns true if dir belongs to a module nested in the module at root, rather than to that module
+func (h *ModuleDocHandler) inNestedModule(root, dir string) bool {
+	for _, m := range h.modules {
+		if m == root || !strings.HasPrefix(m, root+string(filepath.Separator)) {
+			continue
+		}
+		if dir == m || strings.HasPrefix(dir, m+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// index returns the documentation of a package, parsing it on first use
+func (h *ModuleDocHandler) index(pkgPath string) []*doc.Package {
+	if pkgs, ok := h.pkgs[pkgPath]; ok {
+		return pkgs
+	}
+
+	var res []*doc.Package
+	if dir, ok := h.packageDir(pkgPath); ok {
+		ps, err := parseDocPackages(newOSSourceScanner(dir, parser.ParseComments), ".")
+		if err == nil {
+			for n, p := range ps {
+				if strings.HasSuffix(n, "_test") && len(ps) > 1 {
+					// external test packages have an import path of their own
+					continue
+				}
+				res = append(res, doc.New(p, pkgPath, 0))
+			}
+		}
+	}
+	h.pkgs[pkgPath] = res
+	return res
+}
+
+// find returns the documentation of the named type in the package pkgPath
+func (h *ModuleDocHandler) find(pkgPath, name string) *doc.Type {
+	if pkgPath == "" || name == "" {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return findDocType(name, h.index(pkgPath)...)
+}
+
+// Type retrieves documentation for a type, indexing its package if need be
+func (h *ModuleDocHandler) Type(t reflect.Type) string {
+	doct := h.find(t.PkgPath(), t.Name())
+	if doct == nil {
+		return ""
+	}
+	return strings.TrimSpace(doct.Doc)
+}
+
+// Method retrieves documentation for an interface's method, indexing its package if need be
+func (h *ModuleDocHandler) Method(parent reflect.Type, method reflect.Method) string {
+	doct := h.find(parent.PkgPath(), parent.Name())
+	if doct == nil {
+		return ""
+	}
+	return memberDocs(doct)[method.Name]
+}
+
+// Field retrieves documentation for a struct field, indexing its package if need be
+func (h *ModuleDocHandler) Field(parent reflect.Type, field reflect.StructField) string {
+	doct := h.find(parent.PkgPath(), parent.Name())
+	if doct == nil {
+		return ""
+	}
+	return memberDocs(doct)[field.Name]
+}
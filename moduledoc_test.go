@@ -0,0 +1,87 @@
+package bel
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestModuleDocHandler(t *testing.T) {
+	handler, err := NewModuleDocHandler(".")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	parsed, err := NewParsedSourceDocHandler(".", "github.com/32leaves/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(handler.pkgs) > 0 {
+		t.Errorf("packages were indexed before use")
+	}
+	for _, v := range []interface{}{(*InterfaceWithDocumentation)(nil), AnotherTestStruct{}} {
+		act, err := Extract(v, WithDocumentation(handler), FollowStructs, SortAlphabetically)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		exp, err := Extract(v, WithDocumentation(parsed), FollowStructs, SortAlphabetically)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := deep.Equal(act, exp); diff != nil {
+			t.Error(diff)
+		}
+	}
+	if len(handler.pkgs) != 1 {
+		t.Errorf("expected exactly one indexed package, got %d", len(handler.pkgs))
+	}
+}
+
+func TestModuleDocHandlerLayout(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"go.mod":              "module example.com/app\n",
+		"pkgs/v1/api.go":      "package api\n\n// Ticket is in a directory named differently than its package\ntype Ticket struct{}\n",
+		"pkgs/v1/ext_test.go": "package api_test\n\n// Ticket is part of the test package\ntype Ticket struct{}\n",
+		"nested/go.mod":       "module example.com/nested\n",
+		"nested/sub/sub.go":   "package sub\n\n// Thing lives in a nested module\ntype Thing struct{}\n",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the handler finds the module of a subdirectory, too
+	handler, err := NewModuleDocHandler(filepath.Join(dir, "pkgs"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		PkgPath string
+		Name    string
+		Doc     string
+	}{
+		{"example.com/app/pkgs/v1", "Ticket", "Ticket is in a directory named differently than its package\n"},
+		{"example.com/nested/sub", "Thing", "Thing lives in a nested module\n"},
+		{"example.com/app/nested/sub", "Thing", ""},
+		{"example.com/app/pkgs/v1", "DoesNotExist", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.PkgPath+"."+test.Name, func(t *testing.T) {
+			var doc string
+			if doct := handler.find(test.PkgPath, test.Name); doct != nil {
+				doc = doct.Doc
+			}
+			if doc != test.Doc {
+				t.Errorf("unexpected documentation: %q", doc)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package bel
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ModuleWriter opens the writer a Typescript module is written to. The name
+// of the module has no file extension, e.g. "index" or "mypkg".
+type ModuleWriter func(name string) (io.WriteCloser, error)
+
+// ModulesToDir writes each module to a <name>.ts file in dir, creating dir if need be
+func ModulesToDir(dir string) ModuleWriter {
+	return func(name string) (io.WriteCloser, error) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(filepath.Join(dir, name+".ts"))
+	}
+}
+
+// indexModuleName is the name of the barrel module re-exporting all other modules
+const indexModuleName = "index"
+
+// runtimeModuleName is the module containing the runtime code shared by all other modules, e.g. the JSON-RPC transport
+const runtimeModuleName = "runtime"
+
+// defaultModuleName is the module we place types in which have no origin package
+const defaultModuleName = "types"
+
+// tsModule is a single Typescript module produced from one Go package
+type tsModule struct {
+	Name    string
+	PkgPath string
+	Types   []TypescriptType
+	// Imports maps module names to the type names we import from them
+	Imports map[string][]string
+	// Values maps module names to the values (e.g. classes) we import from them
+	Values map[string][]string
+}
+
+// RenderModules produces one Typescript module per Go package the types originate from.
+// Cross-package references are satisfied using import statements, and an index module
+// re-exports everything.
+func RenderModules(types []TypescriptType, out ModuleWriter, cfg ...GenerateOption) error {
+	opts := newGenerateOptions(cfg)
+	if opts.Namespace != "" {
+		return fmt.Errorf("namespaces are not supported when rendering modules")
+	}
+
+	mods, err := planModules(types, opts)
+	if err != nil {
+		return err
+	}
+	if runtime := runtimeDecls(opts); len(runtime) > 0 {
+		err := renderModule(out, runtimeModuleName, func(w io.Writer) error {
+			p := &tsPrinter{format: opts.Format}
+			p.preamble(opts.Preamble)
+			p.decls(runtime)
+			_, err := io.WriteString(w, p.String())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, mod := range mods {
+		var imports strings.Builder
+		for _, dep := range sortedKeys(mod.Imports, mod.Values) {
+			if values := mod.Values[dep]; len(values) > 0 {
+				fmt.Fprintf(&imports, "import { %s } from \"./%s\";\n", strings.Join(values, ", "), dep)
+			}
+			if types := mod.Imports[dep]; len(types) > 0 {
+				fmt.Fprintf(&imports, "import type { %s } from \"./%s\";\n", strings.Join(types, ", "), dep)
+			}
+		}
+
+		modcfg := append(cfg[:len(cfg):len(cfg)], GenerateAdditionalPreamble(imports.String()), generateExternalRuntime, generateInContext(types))
+		err := renderModule(out, mod.Name, func(w io.Writer) error {
+			return Render(mod.Types, append(modcfg, GenerateOutputTo(w))...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return renderModule(out, indexModuleName, func(w io.Writer) error {
+		if _, err := io.WriteString(w, opts.Preamble); err != nil {
+			return err
+		}
+		if len(runtimeDecls(opts)) > 0 {
+			if _, err := fmt.Fprintf(w, "export * from \"./%s\";\n", runtimeModuleName); err != nil {
+				return err
+			}
+		}
+		for _, mod := range mods {
+			export := "*"
+			if opts.ModuleFacades {
+				export = "* as " + mod.Name
+			}
+			if _, err := fmt.Fprintf(w, "export %s from \"./%s\";\n", export, mod.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// generateExternalRuntime omits the runtime code, which RenderModules renders into a module of its own
+func generateExternalRuntime(opt *GenerateOptions) {
+	opt.externalRuntime = true
+}
+
+// generateInContext makes the types of all modules known when rendering a single one
+func generateInContext(types []TypescriptType) GenerateOption {
+	return func(opt *GenerateOptions) {
+		opt.allTypes = types
+	}
+}
+
+func renderModule(out ModuleWriter, name string, render func(w io.Writer) error) error {
+	w, err := out(name)
+	if err != nil {
+		return err
+	}
+
+	err = render(w)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot render module %s: %v", name, err)
+	}
+	return nil
+}
+
+// planModules groups types by their origin package and determines the imports each module needs.
+// Types of the same name in different packages are fine as long as references to them are unambiguous
+// and, unless facades keep them apart, the index module does not re-export both.
+func planModules(types []TypescriptType, opts GenerateOptions) ([]tsModule, error) {
+	var (
+		mods   []*tsModule
+		bypkg  = make(map[string]*tsModule)
+		byname = make(map[string][]*tsModule)
+		names  = map[string]bool{indexModuleName: true, runtimeModuleName: true}
+	)
+	for _, t := range types {
+		mod, ok := bypkg[t.PkgPath]
+		if !ok {
+			mod = &tsModule{
+				Name:    moduleName(t.PkgPath, names),
+				PkgPath: t.PkgPath,
+				Imports: make(map[string][]string),
+				Values:  make(map[string][]string),
+			}
+			names[mod.Name] = true
+			bypkg[t.PkgPath] = mod
+			mods = append(mods, mod)
+		}
+		mod.Types = append(mod.Types, t)
+		if !containsModule(byname[t.Name], mod) {
+			byname[t.Name] = append(byname[t.Name], mod)
+		}
+	}
+
+	if !opts.ModuleFacades {
+		for _, t := range types {
+			if decl := byname[t.Name]; len(decl) > 1 {
+				return nil, fmt.Errorf("type %s is declared in both %s and %s: use module facades or rename one of them", t.Name, decl[0].PkgPath, decl[1].PkgPath)
+			}
+		}
+	}
+
+	decls := make(map[string]TypescriptType)
+	for _, t := range types {
+		if _, exists := decls[t.Name]; !exists {
+			decls[t.Name] = t
+		}
+	}
+
+	for _, mod := range mods {
+		var (
+			// typeImports and valueImports map the names we import to the module they're declared in
+			typeImports  = make(map[string]*tsModule)
+			valueImports = make(map[string]*tsModule)
+			err          error
+		)
+		for _, t := range mod.Types {
+			// resolve finds the module declaring a type referenced by t, or nil if that's this module or none
+			resolve := func(name string) *tsModule {
+				decl := byname[name]
+				if len(decl) == 0 || containsModule(decl, mod) {
+					return nil
+				}
+				if len(decl) > 1 {
+					if err == nil {
+						err = fmt.Errorf("%s references %s, which is declared in both %s and %s", t.Name, name, decl[0].PkgPath, decl[1].PkgPath)
+					}
+					return nil
+				}
+				return decl[0]
+			}
+			walkReferences(t, func(name string) {
+				dep := resolve(name)
+				if dep == nil {
+					return
+				}
+				typeImports[name] = dep
+				for _, v := range valueReferences(decls[name], decls, opts) {
+					if dep := resolve(v.Type); dep != nil {
+						valueImports[v.Value] = dep
+					}
+				}
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		for name, dep := range valueImports {
+			mod.Values[dep.Name] = append(mod.Values[dep.Name], name)
+		}
+		for name, dep := range typeImports {
+			if _, ok := valueImports[name]; !ok {
+				mod.Imports[dep.Name] = append(mod.Imports[dep.Name], name)
+			}
+		}
+		for _, t := range mod.Types {
+			if opts.JSONRPC != nil && isServiceInterface(t) {
+				// the clients' constructor takes a transport
+				mod.Imports[runtimeModuleName] = []string{"JSONRPCTransport"}
+			}
+			if opts.Mocks && isServiceInterface(t) {
+				// mocks extend the base class
+				mod.Values[runtimeModuleName] = []string{"ServiceMock"}
+			}
+		}
+		for _, imp := range mod.Imports {
+			sort.Strings(imp)
+		}
+		for _, imp := range mod.Values {
+			sort.Strings(imp)
+		}
+	}
+
+	res := make([]tsModule, len(mods))
+	for i, mod := range mods {
+		res[i] = *mod
+	}
+	return res, nil
+}
+
+func containsModule(mods []*tsModule, mod *tsModule) bool {
+	for _, m := range mods {
+		if m == mod {
+			return true
+		}
+	}
+	return false
+}
+
+// valueReference is a value the generated code uses when referring to a type, e.g. a class, along with the type
+// which declares it
+type valueReference struct {
+	Value string
+	Type  string
+}
+
+// valueReferences returns the values the generated code uses when it refers to t, e.g. to construct a class from JSON
+// or to check a value using a type guard
+func valueReferences(t TypescriptType, decls map[string]TypescriptType, opts GenerateOptions) []valueReference {
+	var res []valueReference
+	if opts.Classes && isClass(t) {
+		res = append(res, valueReference{Value: t.Name, Type: t.Name})
+	}
+	if opts.TypeGuards && hasGuard(t) {
+		res = append(res, valueReference{Value: guardName(t.Name), Type: t.Name})
+	}
+	if opts.Classes && t.Kind == TypescriptUnionKind {
+		// classes decode and encode the variants of unions
+		for _, p := range t.Params {
+			if p.Kind == TypescriptSimpleKind && isClass(decls[p.Name]) {
+				res = append(res, valueReference{Value: p.Name, Type: p.Name})
+			}
+		}
+	}
+	return res
+}
+
+// moduleName derives a unique module name from a Go package path
+func moduleName(pkgPath string, taken map[string]bool) string {
+	if pkgPath == "" {
+		pkgPath = defaultModuleName
+	}
+
+	// module names double as identifiers when we produce module facades
+	sanitize := func(r rune) rune {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}
+
+	segments := strings.Split(strings.Trim(pkgPath, "/"), "/")
+	var name string
+	for i := len(segments) - 1; i >= 0; i-- {
+		name = strings.Map(sanitize, strings.Join(segments[i:], "_"))
+		if !taken[name] {
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// walkReferences calls fn for the name of every simple type t refers to
+func walkReferences(t TypescriptType, fn func(name string)) {
+	if t.Kind == TypescriptSimpleKind && t.Name != "" {
+		fn(t.Name)
+	}
+	for _, p := range t.Params {
+		walkReferences(p, fn)
+	}
+	for _, m := range t.Members {
+		walkReferences(m.Type, fn)
+		for _, a := range m.Args {
+			walkReferences(a.Type, fn)
+		}
+	}
+}
+
+// sortedKeys returns the keys of all maps, sorted and without duplicates
+func sortedKeys(ms ...map[string][]string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, m := range ms {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				res = append(res, k)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,359 @@
+package bel
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestPlanModules(t *testing.T) {
+	ref := func(name string) TypescriptType {
+		return TypescriptType{Name: name, Kind: TypescriptSimpleKind}
+	}
+	types := []TypescriptType{
+		{
+			Name:    "User",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/users",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Role", Type: ref("Role")}},
+				{TypedElement: TypedElement{Name: "Name", Type: ref("string")}},
+			},
+		},
+		{
+			Name:    "Role",
+			Kind:    TypescriptEnumKind,
+			PkgPath: "example.com/api/auth",
+		},
+		{
+			Name:    "UserService",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/service",
+			Members: []TypescriptMember{
+				{
+					TypedElement: TypedElement{Name: "ListUsers", Type: TypescriptType{Kind: TypescriptArrayKind, Params: []TypescriptType{ref("User")}}},
+					IsFunction:   true,
+					Args:         []TypedElement{{Name: "arg0", Type: ref("Role")}},
+				},
+			},
+		},
+		{
+			Name:    "Token",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/other/auth",
+		},
+	}
+
+	mods, err := planModules(types, GenerateOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	type result struct {
+		Name    string
+		Types   []string
+		Imports map[string][]string
+	}
+	act := make([]result, len(mods))
+	for i, mod := range mods {
+		act[i] = result{Name: mod.Name, Imports: mod.Imports}
+		for _, t := range mod.Types {
+			act[i].Types = append(act[i].Types, t.Name)
+		}
+	}
+
+	expectation := []result{
+		{Name: "users", Types: []string{"User"}, Imports: map[string][]string{"auth": {"Role"}}},
+		{Name: "auth", Types: []string{"Role"}, Imports: map[string][]string{}},
+		{Name: "service", Types: []string{"UserService"}, Imports: map[string][]string{"auth": {"Role"}, "users": {"User"}}},
+		{Name: "other_auth", Types: []string{"Token"}, Imports: map[string][]string{}},
+	}
+	diff := deep.Equal(expectation, act)
+	for _, d := range diff {
+		t.Error(d)
+	}
+}
+
+func TestPlanModulesCollisions(t *testing.T) {
+	ref := func(name string) TypescriptType {
+		return TypescriptType{Name: name, Kind: TypescriptSimpleKind}
+	}
+	user := func(pkg string, members ...TypescriptMember) TypescriptType {
+		return TypescriptType{Name: "User", Kind: TypescriptInterfaceKind, PkgPath: pkg, Members: members}
+	}
+	tests := []struct {
+		Name    string
+		Types   []TypescriptType
+		Facades bool
+		Error   bool
+	}{
+		{"flat index", []TypescriptType{user("example.com/a"), user("example.com/b")}, false, true},
+		{"facades", []TypescriptType{user("example.com/a"), user("example.com/b")}, true, false},
+		{"local reference", []TypescriptType{
+			user("example.com/a", TypescriptMember{TypedElement: TypedElement{Name: "Self", Type: ref("User")}}),
+			user("example.com/b"),
+		}, true, false},
+		{"ambiguous reference", []TypescriptType{
+			user("example.com/a"),
+			user("example.com/b"),
+			{Name: "Group", Kind: TypescriptInterfaceKind, PkgPath: "example.com/c", Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Owner", Type: ref("User")}},
+			}},
+		}, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := planModules(test.Types, GenerateOptions{ModuleFacades: test.Facades})
+			if test.Error && err == nil {
+				t.Errorf("expected an error")
+			} else if !test.Error && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderModulesRejectsNamespace(t *testing.T) {
+	err := RenderModules(nil, ModulesToDir(t.TempDir()), GenerateNamespace("foo"))
+	if err == nil {
+		t.Error("expected an error when rendering modules with a namespace")
+	}
+}
+
+type memModules map[string]*bytes.Buffer
+
+func (m memModules) open(name string) (io.WriteCloser, error) {
+	b := &bytes.Buffer{}
+	m[name] = b
+	return nopCloser{b}, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestRenderModulesIndex(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "User", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/users"},
+		{Name: "Token", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/auth-tokens"},
+	}
+
+	tests := []struct {
+		Name        string
+		Opts        []GenerateOption
+		Expectation string
+	}{
+		{"flat", nil, "export * from \"./users\";\nexport * from \"./auth_tokens\";\n"},
+		{"facades", []GenerateOption{GenerateModuleFacades}, "export * as users from \"./users\";\nexport * as auth_tokens from \"./auth_tokens\";\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			mods := make(memModules)
+			err := RenderModules(types, mods.open, append(test.Opts, GeneratePreamble(""))...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if idx := mods["index"].String(); idx != test.Expectation {
+				t.Errorf("unexpected index module: %q", idx)
+			}
+		})
+	}
+}
+
+func TestRenderModules(t *testing.T) {
+	types := []TypescriptType{
+		{
+			Name:    "User",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/users",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Role", Type: TypescriptType{Name: "Role", Kind: TypescriptSimpleKind}}},
+			},
+		},
+		{Name: "Role", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/auth"},
+	}
+
+	dir := filepath.Join(t.TempDir(), "does", "not", "exist")
+	err := RenderModules(types, ModulesToDir(dir), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := map[string]string{
+		"users.ts": "import type { Role } from \"./auth\";\n\n" +
+			"export interface User {\n    Role: Role\n}\n",
+		"auth.ts":  "export interface Role {\n}\n",
+		"index.ts": "export * from \"./users\";\nexport * from \"./auth\";\n",
+	}
+	act := make(map[string]string)
+	for fn := range expectation {
+		fc, err := ioutil.ReadFile(filepath.Join(dir, fn))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		act[fn] = string(fc)
+	}
+	if diff := deep.Equal(act, expectation); diff != nil {
+		for fn := range expectation {
+			t.Logf("%s:\n%s", fn, act[fn])
+		}
+		t.Error(diff)
+	}
+}
+
+func TestRenderModulesRuntime(t *testing.T) {
+	types := []TypescriptType{
+		{
+			Name:    "UserService",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/service",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Ping", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}, IsFunction: true},
+			},
+		},
+		{Name: "User", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/users"},
+	}
+
+	tests := []struct {
+		Name    string
+		Opts    []GenerateOption
+		Runtime []string
+		Imports string
+	}{
+		{"clients", []GenerateOption{GenerateJSONRPCClients()}, []string{"export function httpJSONRPCTransport"},
+			"import type { JSONRPCTransport } from \"./runtime\";\n"},
+		{"mocks", []GenerateOption{GenerateMocks}, []string{"export class ServiceMock"},
+			"import { ServiceMock } from \"./runtime\";\n"},
+		{"clients and mocks", []GenerateOption{GenerateJSONRPCClients(), GenerateMocks}, []string{"export function httpJSONRPCTransport", "export class ServiceMock"},
+			"import { ServiceMock } from \"./runtime\";\nimport type { JSONRPCTransport } from \"./runtime\";\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			mods := make(memModules)
+			err := RenderModules(types, mods.open, append(test.Opts, GeneratePreamble(""))...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			rt := mods[runtimeModuleName].String()
+			for _, exp := range test.Runtime {
+				if !strings.Contains(rt, exp) {
+					t.Errorf("runtime module does not contain %q:\n%s", exp, rt)
+				}
+			}
+			if svc := mods["service"].String(); !strings.HasPrefix(svc, test.Imports+"\n") || strings.Contains(svc, test.Runtime[0]) {
+				t.Errorf("service module does not import the runtime:\n%s", svc)
+			}
+			if users := mods["users"].String(); strings.Contains(users, "runtime") {
+				t.Errorf("users module refers to the runtime:\n%s", users)
+			}
+			if idx, exp := mods["index"].String(), "export * from \"./runtime\";\nexport * from \"./service\";\nexport * from \"./users\";\n"; idx != exp {
+				t.Errorf("unexpected index module: %q", idx)
+			}
+		})
+	}
+}
+
+func TestRenderModulesClasses(t *testing.T) {
+	ref := func(name string) TypescriptType {
+		return TypescriptType{Name: name, Kind: TypescriptSimpleKind}
+	}
+	types := []TypescriptType{
+		{
+			Name:    "Request",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/a",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "id", Type: ref("string")}},
+			},
+		},
+		{
+			Name:    "Batch",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/b",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "first", Type: ref("Request")}},
+			},
+		},
+	}
+
+	mods := make(memModules)
+	err := RenderModules(types, mods.open, GenerateClasses, GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b := mods["b"].String()
+	for _, exp := range []string{
+		"import { Request } from \"./a\";\n\n",
+		`res["first"] = Request.fromJSON(obj["first"]);`,
+		`"first": this["first"].toJSON(),`,
+	} {
+		if !strings.Contains(b, exp) {
+			t.Errorf("module b does not contain %q:\n%s", exp, b)
+		}
+	}
+
+	// the variants of unions are constructed, too
+	types = append(types,
+		TypescriptType{Name: "Job", Kind: TypescriptUnionKind, PkgPath: "example.com/api/a", Params: []TypescriptType{ref("Request"), ref("Batch")}},
+		TypescriptType{Name: "Queue", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/c", Members: []TypescriptMember{
+			{TypedElement: TypedElement{Name: "next", Type: ref("Job")}},
+		}},
+	)
+	plan, err := planModules(types, GenerateOptions{Classes: true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	queue := plan[2]
+	if diff := deep.Equal(queue.Values, map[string][]string{"a": {"Request"}, "b": {"Batch"}}); diff != nil {
+		t.Errorf("unexpected value imports: %v", diff)
+	}
+	if diff := deep.Equal(queue.Imports, map[string][]string{"a": {"Job"}}); diff != nil {
+		t.Errorf("unexpected type imports: %v", diff)
+	}
+}
+
+func TestRenderModulesTypeGuards(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "Request", Kind: TypescriptInterfaceKind, PkgPath: "example.com/api/a"},
+		{
+			Name:    "Batch",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: "example.com/api/b",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "first", Type: TypescriptType{Name: "Request", Kind: TypescriptSimpleKind}}},
+			},
+		},
+	}
+
+	mods := make(memModules)
+	err := RenderModules(types, mods.open, GenerateTypeGuards, GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b := mods["b"].String()
+	for _, exp := range []string{
+		"import { isRequest } from \"./a\";\nimport type { Request } from \"./a\";\n\n",
+		`&& isRequest((v as { [key: string]: unknown })["first"]);`,
+	} {
+		if !strings.Contains(b, exp) {
+			t.Errorf("module b does not contain %q:\n%s", exp, b)
+		}
+	}
+}
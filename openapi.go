@@ -0,0 +1,41 @@
+package bel
+
+import (
+	"encoding/json"
+)
+
+// openAPIComponents is an OpenAPI 3 document containing only schema components
+type openAPIComponents struct {
+	Components struct {
+		Schemas map[string]*jsonSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// RenderOpenAPI produces an OpenAPI 3 document which describes each type as schema
+// in its components section. Nullable, enum, description and format annotations are
+// taken from the extracted types. By default the document is written as YAML; use
+// GenerateJSON to produce JSON instead.
+func RenderOpenAPI(types []TypescriptType, cfg ...GenerateOption) error {
+	return renderOpenAPI(types, newGenerateOptions(cfg))
+}
+
+func renderOpenAPI(types []TypescriptType, opts GenerateOptions) error {
+	schemas, err := newSchemaBuilder(types, "#/components/schemas/", true).definitions(types)
+	if err != nil {
+		return err
+	}
+	var doc openAPIComponents
+	doc.Components.Schemas = schemas
+
+	if opts.JSON {
+		enc := json.NewEncoder(opts.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	fc, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return jsonToYAML(opts.Out, fc)
+}
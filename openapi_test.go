@@ -0,0 +1,138 @@
+package bel
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+// OpenAPIPayload is sent over the wire
+type OpenAPIPayload struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Kind      *MyEnum   `json:"kind,omitempty"`
+	Parent    *AnotherTestStruct
+	Ratio     float32
+}
+
+func TestRenderOpenAPI(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	docs, err := NewParsedSourceDocHandler(".", "github.com/32leaves/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(OpenAPIPayload{}, FollowStructs, TimeAsString, WithEnumerations(handler), WithDocumentation(docs), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderOpenAPI(extract, GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := `components:
+  schemas:
+    AnotherTestStruct:
+      type: "object"
+      description: "AnotherTestStruct is just yet another struct"
+      properties:
+        Bar:
+          type: "boolean"
+          description: "Bar as well"
+        Foo:
+          type: "string"
+          description: "Foo has some documentation"
+      required:
+        - "Bar"
+        - "Foo"
+      additionalProperties: false
+    MyEnum:
+      type: "string"
+      enum:
+        - "member-one"
+        - "member-two"
+        - "member-three"
+    OpenAPIPayload:
+      type: "object"
+      description: "OpenAPIPayload is sent over the wire"
+      properties:
+        Parent:
+          nullable: true
+          allOf:
+            - $ref: "#/components/schemas/AnotherTestStruct"
+        Ratio:
+          type: "number"
+          format: "float"
+        createdAt:
+          type: "string"
+          format: "date-time"
+        id:
+          type: "integer"
+          format: "int64"
+        kind:
+          nullable: true
+          allOf:
+            - $ref: "#/components/schemas/MyEnum"
+      required:
+        - "Parent"
+        - "Ratio"
+        - "createdAt"
+        - "id"
+      additionalProperties: false
+`
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected OpenAPI document:\n%s", act)
+	}
+}
+
+func TestRenderOpenAPIJSON(t *testing.T) {
+	extract, err := Extract(AnotherTestStruct{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderOpenAPI(extract, GenerateOutputTo(&out), GenerateJSON)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var act map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &act); err != nil {
+		t.Error(err)
+		return
+	}
+	expectation := map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"AnotherTestStruct": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"Foo": map[string]interface{}{"type": "string"},
+						"Bar": map[string]interface{}{"type": "boolean"},
+					},
+					"required":             []interface{}{"Foo", "Bar"},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+	diff := deep.Equal(expectation, act)
+	for _, d := range diff {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,544 @@
+package bel
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// tsDecl is a top-level declaration of the TypeScript syntax tree
+type tsDecl interface {
+	isDecl()
+}
+
+// tsType is a type expression of the TypeScript syntax tree
+type tsType interface {
+	isType()
+}
+
+// tsNamespace is `export namespace Name { ... }`
+type tsNamespace struct {
+	Name    string
+	Declare bool
+	Body    []tsDecl
+}
+
+// tsInterface is `export interface Name { ... }`
+type tsInterface struct {
+	Comment string
+	Name    string
+	Members []tsMember
+}
+
+// tsEnum is `export enum Name { ... }`
+type tsEnum struct {
+	Comment string
+	Name    string
+	Declare bool
+	Members []tsEnumMember
+}
+
+// tsEnumMember is a member of an enum. Its value is a literal.
+type tsEnumMember struct {
+	Name  string
+	Value string
+}
+
+// tsTypeAlias is `export type Name = Type;`
+type tsTypeAlias struct {
+	Comment string
+	Name    string
+	Type    tsType
+}
+
+// tsRaw is code which was produced elsewhere, indented using templateIndent
+type tsRaw struct {
+	Code string
+}
+
+// tsMember is a property or method signature
+type tsMember struct {
+	Comment  string
+	Name     string
+	Optional bool
+	Method   bool
+	Params   []tsParam
+	Type     tsType
+}
+
+// tsParam is a method parameter
+type tsParam struct {
+	Name string
+	Type tsType
+}
+
+// tsTypeRef refers to a type by name, or is a literal type. Args are its type arguments, e.g. for Promise<T>.
+type tsTypeRef struct {
+	Name string
+	Args []tsType
+}
+
+// tsArray is `Elem[]`
+type tsArray struct {
+	Elem tsType
+}
+
+// tsMap is `{ [key: Key]: Value }`
+type tsMap struct {
+	Key   tsType
+	Value tsType
+}
+
+// tsObject is an anonymous object type
+type tsObject struct {
+	Members []tsMember
+}
+
+// tsUnion is `A | B`
+type tsUnion struct {
+	Types []tsType
+}
+
+func (tsNamespace) isDecl() {}
+func (tsInterface) isDecl() {}
+func (tsEnum) isDecl()      {}
+func (tsTypeAlias) isDecl() {}
+func (tsRaw) isDecl()       {}
+
+func (tsTypeRef) isType() {}
+func (tsArray) isType()   {}
+func (tsMap) isType()     {}
+func (tsObject) isType()  {}
+func (tsUnion) isType()   {}
+
+// renderTypescript produces TypeScript code by building a syntax tree and printing it
+func renderTypescript(types []TypescriptType, opts GenerateOptions) error {
+	if err := validateGenerateOptions(opts); err != nil {
+		return err
+	}
+
+	decls, err := tsDeclarations(types, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Namespace != "" {
+		decls = []tsDecl{tsNamespace{Name: opts.Namespace, Declare: opts.Declarations, Body: decls}}
+	}
+
+	p := &tsPrinter{format: opts.Format}
+	p.preamble(opts.Preamble)
+	p.decls(decls)
+
+	_, err = io.WriteString(opts.Out, p.String())
+	return err
+}
+
+// tsDeclarations builds the declarations for types, including the runtime code and extras (e.g. guards) the options ask for
+func tsDeclarations(types []TypescriptType, opts GenerateOptions) ([]tsDecl, error) {
+	var (
+		res     []tsDecl
+		guards  = newGuardRenderer(opts.knownTypes(types))
+		classes = newClassRenderer(opts.knownTypes(types))
+		clients *jsonrpcClientRenderer
+		mocks   = &mockRenderer{promises: opts.JSONRPC != nil}
+	)
+	if opts.JSONRPC != nil {
+		clients = &jsonrpcClientRenderer{opts: *opts.JSONRPC}
+	}
+	if !opts.externalRuntime {
+		res = append(res, runtimeDecls(opts)...)
+	}
+
+	b := &tsBuilder{promises: clients != nil}
+	for _, t := range types {
+		if opts.Classes && isClass(t) {
+			res = append(res, tsRaw{classes.Class(t)})
+		} else {
+			decl, err := b.decl(t, opts)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", t.Name, err)
+			}
+			res = append(res, decl)
+		}
+
+		var extras []string
+		if opts.TypeGuards {
+			extras = append(extras, guards.Guard(t))
+		}
+		if clients != nil {
+			extras = append(extras, clients.Client(t))
+		}
+		if opts.Mocks {
+			extras = append(extras, mocks.Mock(t))
+		}
+		for _, e := range extras {
+			if e != "" {
+				res = append(res, tsRaw{e})
+			}
+		}
+	}
+	return res, nil
+}
+
+// runtimeDecls produces the runtime code the generated code needs, e.g. the JSON-RPC transport of the clients
+func runtimeDecls(opts GenerateOptions) []tsDecl {
+	var res []tsDecl
+	if opts.JSONRPC != nil {
+		res = append(res, tsRaw{jsonrpcRuntime})
+	}
+	if opts.Mocks {
+		res = append(res, tsRaw{mockRuntime})
+	}
+	return res
+}
+
+// tsBuilder converts extracted types to syntax trees
+type tsBuilder struct {
+	// promises is true if methods return promises
+	promises bool
+}
+
+func (b *tsBuilder) decl(t TypescriptType, opts GenerateOptions) (tsDecl, error) {
+	switch t.Kind {
+	case TypescriptEnumKind:
+		if opts.EnumsAsSumTypes {
+			values := make([]tsType, len(t.EnumMembers))
+			for i, m := range t.EnumMembers {
+				values[i] = tsTypeRef{Name: m.Value}
+			}
+			return tsTypeAlias{Comment: t.Comment, Name: t.Name, Type: tsUnion{values}}, nil
+		}
+
+		members := make([]tsEnumMember, len(t.EnumMembers))
+		for i, m := range t.EnumMembers {
+			members[i] = tsEnumMember{Name: m.Name, Value: m.Value}
+		}
+		// declarations within a declared namespace are ambient already
+		return tsEnum{Comment: t.Comment, Name: t.Name, Declare: opts.Declarations && opts.Namespace == "", Members: members}, nil
+	case TypescriptInterfaceKind:
+		members, err := b.members(t.Members)
+		if err != nil {
+			return nil, err
+		}
+		return tsInterface{Comment: t.Comment, Name: t.Name, Members: members}, nil
+	}
+
+	tpe, err := b.typ(t)
+	if err != nil {
+		return nil, err
+	}
+	return tsTypeAlias{Comment: t.Comment, Name: t.Name, Type: tpe}, nil
+}
+
+func (b *tsBuilder) members(members []TypescriptMember) ([]tsMember, error) {
+	res := make([]tsMember, len(members))
+	for i, m := range members {
+		tpe, err := b.typ(m.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", m.Name, err)
+		}
+		res[i] = tsMember{
+			Comment:  m.Comment,
+			Name:     m.Name,
+			Optional: m.IsOptional,
+			Method:   m.IsFunction,
+			Type:     tpe,
+		}
+		if !m.IsFunction {
+			continue
+		}
+
+		if ref, ok := tpe.(tsTypeRef); ok && ref.Name == "" {
+			res[i].Type = tsTypeRef{Name: "void"}
+		}
+		if b.promises {
+			res[i].Type = tsTypeRef{Name: "Promise", Args: []tsType{res[i].Type}}
+		}
+		res[i].Params = make([]tsParam, len(m.Args))
+		for j, a := range m.Args {
+			tpe, err := b.typ(a.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", m.Name, err)
+			}
+			res[i].Params[j] = tsParam{Name: a.Name, Type: tpe}
+		}
+	}
+	return res, nil
+}
+
+func (b *tsBuilder) typ(t TypescriptType) (tsType, error) {
+	switch t.Kind {
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return nil, fmt.Errorf("array needs 1 type param")
+		}
+		elem, err := b.typ(t.Params[0])
+		if err != nil {
+			return nil, err
+		}
+		return tsArray{elem}, nil
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return nil, fmt.Errorf("map needs 2 type params")
+		}
+		key, err := b.typ(t.Params[0])
+		if err != nil {
+			return nil, err
+		}
+		val, err := b.typ(t.Params[1])
+		if err != nil {
+			return nil, err
+		}
+		return tsMap{key, val}, nil
+	case TypescriptUnionKind:
+		variants := make([]tsType, len(t.Params))
+		for i, p := range t.Params {
+			v, err := b.typ(p)
+			if err != nil {
+				return nil, err
+			}
+			variants[i] = v
+		}
+		return tsUnion{variants}, nil
+	case TypescriptInterfaceKind:
+		members, err := b.members(t.Members)
+		if err != nil {
+			return nil, err
+		}
+		return tsObject{members}, nil
+	}
+	return tsTypeRef{Name: t.Name}, nil
+}
+
+// tsPrinter prints TypeScript syntax trees
+type tsPrinter struct {
+	format Format
+	buf    strings.Builder
+	level  int
+}
+
+// String produces the printed code, applying the line ending and quote style of the format
+func (p *tsPrinter) String() string {
+	code := p.buf.String()
+	if !p.format.SingleQuotes && p.format.LineEnding == "\n" {
+		return code
+	}
+
+	lines := strings.SplitAfter(code, "\n")
+	var res strings.Builder
+	res.Grow(len(code))
+	for _, l := range lines {
+		if !strings.HasSuffix(l, "\n") {
+			res.WriteString(l)
+			continue
+		}
+		l = strings.TrimSuffix(l, "\n")
+		if p.format.SingleQuotes {
+			l = quoteLine(l)
+		}
+		res.WriteString(l)
+		res.WriteString(p.format.LineEnding)
+	}
+	return res.String()
+}
+
+func (p *tsPrinter) indent() {
+	for i := 0; i < p.level; i++ {
+		p.buf.WriteString(p.format.Indent)
+	}
+}
+
+// line prints an indented line
+func (p *tsPrinter) line(parts ...string) {
+	p.indent()
+	for _, s := range parts {
+		p.buf.WriteString(s)
+	}
+	p.buf.WriteByte('\n')
+}
+
+func (p *tsPrinter) preamble(preamble string) {
+	if preamble == "" {
+		return
+	}
+	p.buf.WriteString(preamble)
+	if !strings.HasSuffix(preamble, "\n") {
+		p.buf.WriteByte('\n')
+	}
+	p.buf.WriteByte('\n')
+}
+
+func (p *tsPrinter) comment(comment string) {
+	if comment == "" {
+		return
+	}
+	p.line("/**")
+	for _, l := range strings.Split(comment, "\n") {
+		p.line(strings.TrimRight(" * "+l, " "))
+	}
+	p.line(" */")
+}
+
+// docComment formats a comment like the printer does, indented by level, for code which is produced as string
+func docComment(comment string, level int) string {
+	p := &tsPrinter{format: DefaultFormat, level: level}
+	p.comment(comment)
+	return p.buf.String()
+}
+
+// decls prints declarations separated by a blank line
+func (p *tsPrinter) decls(decls []tsDecl) {
+	for i, d := range decls {
+		if i > 0 {
+			p.buf.WriteByte('\n')
+		}
+		p.decl(d)
+	}
+}
+
+func (p *tsPrinter) decl(decl tsDecl) {
+	switch d := decl.(type) {
+	case tsNamespace:
+		p.line("export ", declare(d.Declare), "namespace ", d.Name, " {")
+		p.level++
+		p.decls(d.Body)
+		p.level--
+		p.line("}")
+	case tsInterface:
+		p.comment(d.Comment)
+		p.indent()
+		p.buf.WriteString("export interface " + d.Name + " ")
+		p.object(d.Members)
+		p.buf.WriteByte('\n')
+	case tsEnum:
+		p.comment(d.Comment)
+		p.line("export ", declare(d.Declare), "enum ", d.Name, " {")
+		p.level++
+		for i, m := range d.Members {
+			sep := ","
+			if i == len(d.Members)-1 && !p.format.TrailingCommas {
+				sep = ""
+			}
+			p.line(propertyName(m.Name), " = ", m.Value, sep)
+		}
+		p.level--
+		p.line("}")
+	case tsTypeAlias:
+		p.comment(d.Comment)
+		if u, ok := d.Type.(tsUnion); ok {
+			// unions (e.g. sum types) go on a line of their own
+			p.line("export type ", d.Name, " =")
+			p.level++
+			p.indent()
+			p.typ(u)
+			p.buf.WriteString(";\n")
+			p.level--
+			return
+		}
+		p.indent()
+		p.buf.WriteString("export type " + d.Name + " = ")
+		p.typ(d.Type)
+		p.buf.WriteString(";\n")
+	case tsRaw:
+		for _, l := range strings.Split(strings.TrimRight(d.Code, "\n"), "\n") {
+			if strings.TrimSpace(l) == "" {
+				p.buf.WriteByte('\n')
+				continue
+			}
+			p.line(reindent(l, p.format.Indent))
+		}
+	}
+}
+
+func declare(declare bool) string {
+	if declare {
+		return "declare "
+	}
+	return ""
+}
+
+// object prints the members of an interface or object type, starting at the current position
+func (p *tsPrinter) object(members []tsMember) {
+	p.buf.WriteString("{\n")
+	p.level++
+	for _, m := range members {
+		p.member(m)
+	}
+	p.level--
+	p.indent()
+	p.buf.WriteString("}")
+}
+
+func (p *tsPrinter) member(m tsMember) {
+	p.comment(m.Comment)
+	p.indent()
+	p.buf.WriteString(propertyName(m.Name))
+	if m.Optional {
+		p.buf.WriteByte('?')
+	}
+	if m.Method {
+		p.buf.WriteByte('(')
+		for i, a := range m.Params {
+			if i > 0 {
+				p.buf.WriteString(", ")
+			}
+			p.buf.WriteString(a.Name + ": ")
+			p.typ(a.Type)
+		}
+		p.buf.WriteByte(')')
+	}
+	p.buf.WriteString(": ")
+	p.typ(m.Type)
+	p.buf.WriteString(p.format.MemberTerminator)
+	p.buf.WriteByte('\n')
+}
+
+// typ prints a type expression, starting at the current position
+func (p *tsPrinter) typ(t tsType) {
+	switch t := t.(type) {
+	case tsTypeRef:
+		p.buf.WriteString(t.Name)
+		if len(t.Args) > 0 {
+			p.buf.WriteByte('<')
+			for i, a := range t.Args {
+				if i > 0 {
+					p.buf.WriteString(", ")
+				}
+				p.typ(a)
+			}
+			p.buf.WriteByte('>')
+		}
+	case tsArray:
+		if _, ok := t.Elem.(tsUnion); ok {
+			p.buf.WriteByte('(')
+			p.typ(t.Elem)
+			p.buf.WriteByte(')')
+		} else {
+			p.typ(t.Elem)
+		}
+		p.buf.WriteString("[]")
+	case tsMap:
+		p.buf.WriteString("{ [key: ")
+		p.typ(t.Key)
+		p.buf.WriteString("]: ")
+		p.typ(t.Value)
+		p.buf.WriteString(" }")
+	case tsObject:
+		if len(t.Members) == 0 {
+			p.buf.WriteString("{}")
+			return
+		}
+		p.object(t.Members)
+	case tsUnion:
+		if len(t.Types) == 0 {
+			p.buf.WriteString("never")
+			return
+		}
+		for i, u := range t.Types {
+			if i > 0 {
+				p.buf.WriteString(" | ")
+			}
+			p.typ(u)
+		}
+	}
+}
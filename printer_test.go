@@ -0,0 +1,123 @@
+package bel
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+type PrintedStruct struct {
+	DashedName string `json:"dashed-name"`
+	Nested     struct {
+		Inner struct {
+			Deep string
+		}
+		List []struct {
+			Value int
+		}
+	}
+}
+
+func TestPrintNestedTypes(t *testing.T) {
+	extract, err := Extract(PrintedStruct{}, SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = Render(extract, GeneratePreamble(""), GenerateNamespace("api"), GenerateFormat(PrettierFormat), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := `export namespace api {
+  export interface PrintedStruct {
+    Nested: {
+      Inner: {
+        Deep: string;
+      };
+      List: {
+        Value: number;
+      }[];
+    };
+    "dashed-name": string;
+  }
+}
+`
+	if out.String() != expectation {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestPrintSumTypes(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "Empty", Kind: TypescriptEnumKind},
+		{
+			Name:        "Color",
+			Kind:        TypescriptEnumKind,
+			EnumMembers: []TypescriptEnumMember{{Name: "Red", Value: `"red"`}, {Name: "Blue", Value: `"blue"`}},
+		},
+	}
+
+	var out bytes.Buffer
+	err := Render(types, GeneratePreamble(""), GenerateEnumAsSumType, GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := "export type Empty =\n    never;\n\nexport type Color =\n    \"red\" | \"blue\";\n"
+	if out.String() != expectation {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+// benchmarkTypes produces n interfaces which refer to each other
+func benchmarkTypes(n int) []TypescriptType {
+	res := make([]TypescriptType, n)
+	for i := range res {
+		res[i] = TypescriptType{
+			Name:    fmt.Sprintf("Type%d", i),
+			Comment: fmt.Sprintf("Type%d is a type", i),
+			Kind:    TypescriptInterfaceKind,
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "name", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}},
+				{TypedElement: TypedElement{Name: "count", Type: TypescriptType{Name: "number", Kind: TypescriptSimpleKind}}, IsOptional: true},
+				{TypedElement: TypedElement{Name: "tags", Type: TypescriptType{Kind: TypescriptArrayKind, Params: []TypescriptType{{Name: "string", Kind: TypescriptSimpleKind}}}}},
+				{TypedElement: TypedElement{Name: "refs", Type: TypescriptType{Kind: TypescriptMapKind, Params: []TypescriptType{
+					{Name: "string", Kind: TypescriptSimpleKind},
+					{Name: fmt.Sprintf("Type%d", (i+1)%n), Kind: TypescriptSimpleKind},
+				}}}},
+				{TypedElement: TypedElement{Name: "nested", Type: TypescriptType{Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+					{TypedElement: TypedElement{Name: "flag", Type: TypescriptType{Name: "boolean", Kind: TypescriptSimpleKind}}},
+				}}}},
+			},
+		}
+	}
+	return res
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		types := benchmarkTypes(n)
+		for _, r := range []struct {
+			Name     string
+			Renderer Renderer
+		}{
+			{"printer", TypescriptRenderer},
+			{"template", TemplateRenderer},
+		} {
+			b.Run(fmt.Sprintf("%s-%d", r.Name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					err := Render(types, GenerateUsing(r.Renderer), GenerateOutputTo(ioutil.Discard))
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package bel
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/iancoleman/strcase"
+)
+
+// ReflectEnumHandler discovers enums from their runtime values, hence does not need the source code.
+// Enums are either registered using RegisterEnum, or enumerated automatically (see EnumerateStringers).
+// As constant names are not available at runtime, members are named after their String method rather than
+// after their constants like ParsedSourceEnumHandler does. The same enum may hence have different member names.
+type ReflectEnumHandler struct {
+	mu    sync.Mutex
+	enums map[reflect.Type][]TypescriptEnumMember
+	// stringers caches the result of enumerating types with a String method
+	stringers map[reflect.Type][]TypescriptEnumMember
+
+	enumerate bool
+	min, max  int64
+}
+
+// ReflectEnumOption configures a ReflectEnumHandler
+type ReflectEnumOption func(*ReflectEnumHandler)
+
+// EnumerateStringers makes the handler treat integer types with a String method as enums. Their members are the values
+// from min to max (inclusive) for which String returns a name, rather than the `Color(42)` fallback produced by stringer.
+// To tell such types apart from other integers with a String method (e.g. time.Duration), max+1 must produce that fallback.
+func EnumerateStringers(min, max int64) ReflectEnumOption {
+	return func(h *ReflectEnumHandler) {
+		h.enumerate = true
+		h.min = min
+		h.max = max
+	}
+}
+
+// NewReflectEnumHandler creates a new enum handler that works from runtime values
+func NewReflectEnumHandler(opts ...ReflectEnumOption) *ReflectEnumHandler {
+	h := &ReflectEnumHandler{
+		enums:     make(map[reflect.Type][]TypescriptEnumMember),
+		stringers: make(map[reflect.Type][]TypescriptEnumMember),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RegisterEnum registers the values of a named enum type, e.g. `RegisterEnum(Color(0), Red, Green, Blue)`.
+// Members are named using the String method of the values, or using the value itself for string enums.
+func (h *ReflectEnumHandler) RegisterEnum(enum interface{}, values ...interface{}) error {
+	t := reflect.TypeOf(enum)
+	if t == nil || t.Name() == "" || t.PkgPath() == "" {
+		return fmt.Errorf("enum must be a named type")
+	}
+
+	members := make([]TypescriptEnumMember, 0, len(values))
+	for _, v := range values {
+		rv := reflect.ValueOf(v)
+		if rv.Type() != t {
+			return fmt.Errorf("value %v of enum %s has type %s", v, t.Name(), rv.Type())
+		}
+		m, err := reflectEnumMember(rv)
+		if err != nil {
+			return fmt.Errorf("enum %s: %v", t.Name(), err)
+		}
+		members = append(members, m)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.enums[t] = members
+	return nil
+}
+
+// reflectEnumMember produces the enum member for a value
+func reflectEnumMember(v reflect.Value) (TypescriptEnumMember, error) {
+	var (
+		res  TypescriptEnumMember
+		name string
+	)
+	switch v.Kind() {
+	case reflect.String:
+		res.Value = strconv.Quote(v.String())
+		name = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		res.Value = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		res.Value = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		res.Value = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	default:
+		return res, fmt.Errorf("unsupported kind %v", v.Kind())
+	}
+
+	if _, ok := v.Interface().(fmt.Stringer); ok {
+		s, ok := callString(v)
+		if !ok {
+			return res, fmt.Errorf("cannot name value %s: its String method panics", res.Value)
+		}
+		name = s
+	}
+	if name == "" {
+		return res, fmt.Errorf("cannot name value %s: it has no String method", res.Value)
+	}
+	res.Name = strcase.ToCamel(name)
+	return res, nil
+}
+
+// stringerFallback matches what stringer-generated String methods return for values without a name, e.g. Color(42)
+var stringerFallback = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\(-?[0-9]+\)$`)
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// callString calls the String method of v. It returns false if the method panics, as hand-written ones tend
+// to do for unknown values.
+func callString(v reflect.Value) (res string, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			res, ok = "", false
+		}
+	}()
+	return v.Interface().(fmt.Stringer).String(), true
+}
+
+// isStringerName returns true if String produces a name for v, rather than a fallback or a panic
+func isStringerName(v reflect.Value) bool {
+	s, ok := callString(v)
+	return ok && !stringerFallback.MatchString(s)
+}
+
+// enumerateStringer produces the named values of an integer type with a String method, or nil if it's not an enum
+func (h *ReflectEnumHandler) enumerateStringer(t reflect.Type) []TypescriptEnumMember {
+	if !h.enumerate || h.max < h.min || h.max == math.MaxInt64 || t.PkgPath() == "" || !t.Implements(stringerType) {
+		return nil
+	}
+	signed := false
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		signed = true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil
+	}
+	if members, ok := h.stringers[t]; ok {
+		return members
+	}
+
+	value := func(i int64) (reflect.Value, bool) {
+		v := reflect.New(t).Elem()
+		if signed {
+			if v.OverflowInt(i) {
+				return v, false
+			}
+			v.SetInt(i)
+		} else {
+			if i < 0 || v.OverflowUint(uint64(i)) {
+				return v, false
+			}
+			v.SetUint(uint64(i))
+		}
+		return v, true
+	}
+
+	var members []TypescriptEnumMember
+	if v, ok := value(h.max + 1); ok && !isStringerName(v) {
+		names := make(map[string]bool)
+		for i := h.min; i <= h.max; i++ {
+			v, ok := value(i)
+			if !ok {
+				continue
+			}
+			if !isStringerName(v) {
+				continue
+			}
+			m, err := reflectEnumMember(v)
+			if err != nil || names[m.Name] {
+				continue
+			}
+			names[m.Name] = true
+			members = append(members, m)
+		}
+	}
+	h.stringers[t] = members
+	return members
+}
+
+// IsEnum returns true if the given type was registered, or is an enumerable integer type with a String method
+func (h *ReflectEnumHandler) IsEnum(t reflect.Type) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.enums[t]; ok {
+		return true
+	}
+	return len(h.enumerateStringer(t)) > 0
+}
+
+// GetMember returns all members/values of an enum
+func (h *ReflectEnumHandler) GetMember(t reflect.Type) ([]TypescriptEnumMember, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if members, ok := h.enums[t]; ok {
+		return members, nil
+	}
+	if members := h.enumerateStringer(t); len(members) > 0 {
+		return members, nil
+	}
+	return nil, fmt.Errorf("no enum %s found", t.Name())
+}
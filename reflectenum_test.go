@@ -0,0 +1,147 @@
+package bel
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+// String mimics the code generated by stringer
+func (c Color) String() string {
+	switch c {
+	case Red:
+		return "Red"
+	case Green:
+		return "Green"
+	case Blue:
+		return "Blue"
+	}
+	return "Color(" + strconv.FormatInt(int64(c), 10) + ")"
+}
+
+type Suit uint8
+
+const (
+	Spades Suit = iota
+	Hearts
+)
+
+// String panics for unknown values, as hand-written ones often do
+func (s Suit) String() string {
+	return [...]string{"spades", "hearts"}[s]
+}
+
+type Fruit string
+
+const (
+	Apple  Fruit = "apple"
+	Banana Fruit = "banana"
+)
+
+type StructWithReflectedEnums struct {
+	Color    Color
+	Fruit    Fruit
+	Duration time.Duration
+	Suit     Suit
+}
+
+func TestReflectEnumHandler(t *testing.T) {
+	handler := NewReflectEnumHandler(EnumerateStringers(0, 16))
+	err := handler.RegisterEnum(Fruit(""), Apple, Banana)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	extract, err := Extract(StructWithReflectedEnums{}, WithEnumerations(handler), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	pkg := "github.com/32leaves/bel"
+	expectation := []TypescriptType{
+		{
+			Name:    "Color",
+			Kind:    TypescriptEnumKind,
+			PkgPath: pkg,
+			GoName:  "Color",
+			EnumMembers: []TypescriptEnumMember{
+				{Name: "Red", Value: "0"},
+				{Name: "Green", Value: "1"},
+				{Name: "Blue", Value: "2"},
+			},
+		},
+		{
+			Name:    "Fruit",
+			Kind:    TypescriptEnumKind,
+			PkgPath: pkg,
+			GoName:  "Fruit",
+			EnumMembers: []TypescriptEnumMember{
+				{Name: "Apple", Value: `"apple"`},
+				{Name: "Banana", Value: `"banana"`},
+			},
+		},
+		{
+			Name:    "StructWithReflectedEnums",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: pkg,
+			GoName:  "StructWithReflectedEnums",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Color", Type: TypescriptType{Name: "Color", Kind: TypescriptSimpleKind}}},
+				{TypedElement: TypedElement{Name: "Duration", Type: TypescriptType{Name: "number", Kind: TypescriptSimpleKind, Format: "int64"}}},
+				{TypedElement: TypedElement{Name: "Fruit", Type: TypescriptType{Name: "Fruit", Kind: TypescriptSimpleKind}}},
+				{TypedElement: TypedElement{Name: "Suit", Type: TypescriptType{Name: "Suit", Kind: TypescriptSimpleKind}}},
+			},
+		},
+		{
+			Name:    "Suit",
+			Kind:    TypescriptEnumKind,
+			PkgPath: pkg,
+			GoName:  "Suit",
+			EnumMembers: []TypescriptEnumMember{
+				{Name: "Spades", Value: "0"},
+				{Name: "Hearts", Value: "1"},
+			},
+		},
+	}
+	if diff := deep.Equal(extract, expectation); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestReflectEnumHandlerErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Enum   interface{}
+		Values []interface{}
+		Err    string
+	}{
+		{"unnamed type", 0, nil, "enum must be a named type"},
+		{"wrong type", Fruit(""), []interface{}{Red}, "value Red of enum Fruit has type bel.Color"},
+		{"no names", MyOtherEnum(0), []interface{}{OtherEnumOne}, "enum MyOtherEnum: cannot name value 0: it has no String method"},
+		{"panicking String", Suit(0), []interface{}{Suit(7)}, "enum Suit: cannot name value 7: its String method panics"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := NewReflectEnumHandler().RegisterEnum(test.Enum, test.Values...)
+			if err == nil {
+				t.Errorf("expected error %q", test.Err)
+				return
+			}
+			if err.Error() != test.Err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
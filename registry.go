@@ -0,0 +1,241 @@
+package bel
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds enum and documentation metadata of Go packages, so that generators need neither their sources nor
+// a parser at runtime. The metadata is usually registered by code generated using WriteRegistry (or `bel registry`),
+// which runs when the package is initialized.
+//
+// A Registry is both an EnumHandler and a DocHandler (including field documentation, see FieldDocHandler).
+type Registry struct {
+	mu sync.RWMutex
+	// all maps are indexed by enumKey, members additionally by their name, e.g. github.com/32leaves/bel.MyStruct.Field
+	enums   map[string][]TypescriptEnumMember
+	types   map[string]string
+	fields  map[string]string
+	methods map[string]string
+}
+
+// DefaultRegistry is the registry generated code registers with
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new, empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		enums:   make(map[string][]TypescriptEnumMember),
+		types:   make(map[string]string),
+		fields:  make(map[string]string),
+		methods: make(map[string]string),
+	}
+}
+
+// RegisterEnum registers the members of the enum type name in package pkgPath
+func (r *Registry) RegisterEnum(pkgPath, name string, members ...TypescriptEnumMember) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enums[enumKey(pkgPath, name)] = members
+}
+
+// RegisterTypeDoc registers the documentation of type name in package pkgPath
+func (r *Registry) RegisterTypeDoc(pkgPath, name, doc string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[enumKey(pkgPath, name)] = doc
+}
+
+// RegisterFieldDoc registers the documentation of a field of the struct typeName in package pkgPath
+func (r *Registry) RegisterFieldDoc(pkgPath, typeName, field, doc string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fields[enumKey(pkgPath, typeName)+"."+field] = doc
+}
+
+// RegisterMethodDoc registers the documentation of a method of the interface typeName in package pkgPath
+func (r *Registry) RegisterMethodDoc(pkgPath, typeName, method, doc string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods[enumKey(pkgPath, typeName)+"."+method] = doc
+}
+
+// IsEnum returns true if the given type was registered as enum
+func (r *Registry) IsEnum(t reflect.Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.enums[enumKey(t.PkgPath(), t.Name())]
+	return ok
+}
+
+// GetMember returns all members/values of an enum
+func (r *Registry) GetMember(t reflect.Type) ([]TypescriptEnumMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if members, ok := r.enums[enumKey(t.PkgPath(), t.Name())]; ok {
+		return members, nil
+	}
+	return nil, fmt.Errorf("no enum %s found", t.Name())
+}
+
+// Type retrieves the registered documentation of a type
+func (r *Registry) Type(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.types[enumKey(t.PkgPath(), t.Name())]
+}
+
+// Method retrieves the registered documentation of an interface's method
+func (r *Registry) Method(parent reflect.Type, method reflect.Method) string {
+	if parent.Name() == "" {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.methods[enumKey(parent.PkgPath(), parent.Name())+"."+method.Name]
+}
+
+// Field retrieves the registered documentation of a struct's field
+func (r *Registry) Field(parent reflect.Type, field reflect.StructField) string {
+	if parent.Name() == "" {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fields[enumKey(parent.PkgPath(), parent.Name())+"."+field.Name]
+}
+
+// belImportPath is the import path of this package, which generated registry code refers to
+const belImportPath = "github.com/32leaves/bel"
+
+// WriteRegistry scans the package in srcdir once, and writes a Go file for that package which registers its enums
+// (see NewParsedSourceEnumHandler) and documentation with the DefaultRegistry when the package is initialized.
+// The package's import path is determined using its go.mod (or the GOPATH), or ModulePath if set.
+func WriteRegistry(out io.Writer, srcdir string, opts ...ParsedSourceEnumOption) error {
+	h := &ParsedSourceEnumHandler{minMembers: 1, scanner: newOSSourceScanner(srcdir, parser.ParseComments)}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	importPath := h.modulePath
+	if importPath == "" {
+		var ok bool
+		importPath, ok = newImportPathResolver().ImportPath(srcdir)
+		if !ok {
+			return fmt.Errorf("cannot determine import path of %s", srcdir)
+		}
+	}
+
+	pkgs, err := h.scanner.ParseDir(".")
+	if err != nil {
+		return err
+	}
+	var (
+		name  string
+		files []*ast.File
+	)
+	for n, fs := range pkgs {
+		if strings.HasSuffix(n, "_test") && len(pkgs) > 1 {
+			// external test packages are not part of the package's binary
+			continue
+		}
+		if name != "" {
+			return fmt.Errorf("found packages %s and %s in %s", name, n, srcdir)
+		}
+		name, files = n, fs
+	}
+	if name == "" {
+		return fmt.Errorf("no Go package in %s", srcdir)
+	}
+
+	// enums need to be found first - go/doc modifies the AST
+	enums := findEnums(files, h.minMembers)
+	p := &ast.Package{Name: name, Files: make(map[string]*ast.File, len(files))}
+	for _, f := range files {
+		p.Files[h.scanner.fset.Position(f.Package).Filename] = f
+	}
+	docs := doc.New(p, importPath, 0)
+
+	qualifier := "bel."
+	if importPath == belImportPath {
+		qualifier = ""
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by bel registry. DO NOT EDIT.\n\npackage %s\n\n", name)
+	if qualifier != "" {
+		fmt.Fprintf(&src, "import %q\n\n", belImportPath)
+	}
+	fmt.Fprintf(&src, "func init() {\n")
+
+	pkgPath := strconv.Quote(importPath)
+	enumNames := make([]string, 0, len(enums))
+	for n := range enums {
+		enumNames = append(enumNames, n)
+	}
+	sort.Strings(enumNames)
+	for _, n := range enumNames {
+		fmt.Fprintf(&src, "%sDefaultRegistry.RegisterEnum(%s, %q,\n", qualifier, pkgPath, n)
+		for _, m := range enums[n] {
+			fmt.Fprintf(&src, "%sTypescriptEnumMember{Name: %q, Value: %s", qualifier, m.Name, goString(m.Value))
+			if m.Comment != "" {
+				fmt.Fprintf(&src, ", Comment: %s", goString(m.Comment))
+			}
+			fmt.Fprintf(&src, "},\n")
+		}
+		fmt.Fprintf(&src, ")\n")
+	}
+
+	for _, t := range docs.Types {
+		if d := strings.TrimSpace(t.Doc); d != "" {
+			fmt.Fprintf(&src, "%sDefaultRegistry.RegisterTypeDoc(%s, %q, %s)\n", qualifier, pkgPath, t.Name, goString(d))
+		}
+
+		register := "RegisterFieldDoc"
+		if ts, ok := t.Decl.Specs[0].(*ast.TypeSpec); ok {
+			if _, ok := ts.Type.(*ast.InterfaceType); ok {
+				register = "RegisterMethodDoc"
+			}
+		}
+		members := memberDocs(t)
+		names := make([]string, 0, len(members))
+		for n, d := range members {
+			if d != "" {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(&src, "%sDefaultRegistry.%s(%s, %q, %q, %s)\n", qualifier, register, pkgPath, t.Name, n, goString(members[n]))
+		}
+	}
+	fmt.Fprintf(&src, "}\n")
+
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(res)
+	return err
+}
+
+// goString returns a Go string literal of s, preferring raw strings for values containing quotes (e.g. enum values)
+func goString(s string) string {
+	if strings.Contains(s, `"`) && strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
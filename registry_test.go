@@ -0,0 +1,158 @@
+package bel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestRegistryHandlers(t *testing.T) {
+	pkg := "github.com/32leaves/bel"
+	reg := NewRegistry()
+	reg.RegisterEnum(pkg, "MyEnum", TypescriptEnumMember{Name: "MemberOne", Value: `"member-one"`})
+	reg.RegisterTypeDoc(pkg, "AnotherTestStruct", "AnotherTestStruct is just yet another struct")
+	reg.RegisterFieldDoc(pkg, "AnotherTestStruct", "Foo", "Foo has some documentation")
+	reg.RegisterMethodDoc(pkg, "InterfaceWithDocumentation", "DoSomething", "DoSomething also has documentation")
+
+	if !reg.IsEnum(reflect.TypeOf(MyEnum(""))) {
+		t.Errorf("MyEnum is not an enum")
+	}
+	if reg.IsEnum(reflect.TypeOf(MyOtherEnum(0))) {
+		t.Errorf("MyOtherEnum is an enum")
+	}
+	if _, err := reg.GetMember(reflect.TypeOf(MyOtherEnum(0))); err == nil {
+		t.Errorf("expected error for unregistered enum")
+	}
+
+	extract, err := Extract(AnotherTestStruct{}, WithEnumerations(reg), WithDocumentation(reg))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expectation := []TypescriptType{
+		{
+			Name:    "AnotherTestStruct",
+			Comment: "AnotherTestStruct is just yet another struct",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: pkg,
+			GoName:  "AnotherTestStruct",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "Foo", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}, Comment: "Foo has some documentation"},
+				{TypedElement: TypedElement{Name: "Bar", Type: TypescriptType{Name: "boolean", Kind: TypescriptSimpleKind}}},
+			},
+		},
+	}
+	if diff := deep.Equal(extract, expectation); diff != nil {
+		t.Error(diff)
+	}
+
+	iface := reflect.TypeOf((*InterfaceWithDocumentation)(nil)).Elem()
+	if doc := reg.Method(iface, iface.Method(0)); doc != "DoSomething also has documentation" {
+		t.Errorf("unexpected method documentation: %q", doc)
+	}
+}
+
+func TestWriteRegistry(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"go.mod": "module example.com/api\n",
+		"api.go": `package api
+
+// State is the state of a ticket
+type State string
+
+const (
+	// Open tickets need work
+	Open State = "open"
+	Closed State = "closed" // done
+)
+
+// Ticket is an issue
+type Ticket struct {
+	// ID identifies the ticket
+	ID string
+	State State // the current state
+	assignee string
+}
+
+// Service handles tickets
+type Service interface {
+	// Close closes a ticket
+	Close(id string) error
+}
+
+type undocumented struct{}
+`,
+		"api_test.go": `package api_test
+
+// External is not part of the package
+type External struct{}
+`,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = WriteRegistry(&out, dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := "// Code generated by bel registry. DO NOT EDIT.\n" +
+		`
+package api
+
+import "github.com/32leaves/bel"
+
+func init() {
+	bel.DefaultRegistry.RegisterEnum("example.com/api", "State",
+		bel.TypescriptEnumMember{Name: "Open", Value: ` + "`\"open\"`" + `, Comment: "Open tickets need work"},
+		bel.TypescriptEnumMember{Name: "Closed", Value: ` + "`\"closed\"`" + `, Comment: "done"},
+	)
+	bel.DefaultRegistry.RegisterTypeDoc("example.com/api", "Service", "Service handles tickets")
+	bel.DefaultRegistry.RegisterMethodDoc("example.com/api", "Service", "Close", "Close closes a ticket")
+	bel.DefaultRegistry.RegisterTypeDoc("example.com/api", "State", "State is the state of a ticket")
+	bel.DefaultRegistry.RegisterTypeDoc("example.com/api", "Ticket", "Ticket is an issue")
+	bel.DefaultRegistry.RegisterFieldDoc("example.com/api", "Ticket", "ID", "ID identifies the ticket")
+	bel.DefaultRegistry.RegisterFieldDoc("example.com/api", "Ticket", "State", "the current state")
+}
+`
+	if out.String() != expectation {
+		t.Errorf("unexpected registry:\n%s", out.String())
+	}
+}
+
+func TestWriteRegistryErrors(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"a.go": "package a\n",
+		"b.go": "package b\n",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name    string
+		Dir     string
+		Options []ParsedSourceEnumOption
+	}{
+		{"no import path", t.TempDir(), nil},
+		{"no package", t.TempDir(), []ParsedSourceEnumOption{ModulePath("example.com/empty")}},
+		{"multiple packages", dir, []ParsedSourceEnumOption{ModulePath("example.com/multi")}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WriteRegistry(&out, test.Dir, test.Options...); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
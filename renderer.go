@@ -0,0 +1,29 @@
+package bel
+
+// Renderer produces output for a set of extracted types
+type Renderer interface {
+	Render(types []TypescriptType, opts GenerateOptions) error
+}
+
+// RendererFunc is a function which acts as Renderer
+type RendererFunc func(types []TypescriptType, opts GenerateOptions) error
+
+// Render calls f(types, opts)
+func (f RendererFunc) Render(types []TypescriptType, opts GenerateOptions) error {
+	return f(types, opts)
+}
+
+var (
+	// TypescriptRenderer produces TypeScript code - this is the default renderer
+	TypescriptRenderer Renderer = RendererFunc(renderTypescript)
+	// TemplateRenderer produces TypeScript code using text/template. Its sub-templates can be overridden using GenerateTemplate.
+	TemplateRenderer Renderer = RendererFunc(renderTemplate)
+	// JSONSchemaRenderer produces a JSON Schema document, see RenderJSONSchema
+	JSONSchemaRenderer Renderer = RendererFunc(renderJSONSchema)
+	// OpenAPIRenderer produces OpenAPI component schemas, see RenderOpenAPI
+	OpenAPIRenderer Renderer = RendererFunc(renderOpenAPI)
+	// ZodRenderer produces Zod schemas, see RenderZod
+	ZodRenderer Renderer = RendererFunc(renderZod)
+	// IoTsRenderer produces io-ts codecs, see RenderIoTs
+	IoTsRenderer Renderer = RendererFunc(renderIoTs)
+)
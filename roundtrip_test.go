@@ -74,7 +74,7 @@ func installTsNode(t *testing.T, ws string) bool {
 }
 
 func generateTypescript(t *testing.T, ws string, testdata MyTestStruct) bool {
-	handler, err := NewParsedSourceEnumHandler(".")
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
 	if err != nil {
 		t.Error(err)
 		return false
@@ -0,0 +1,78 @@
+package bel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion is the version of the schema format written by WriteSchema
+const SchemaVersion = 1
+
+// schema is the versioned on-disk form of extracted types
+type schema struct {
+	Version int              `json:"version"`
+	Types   []TypescriptType `json:"types"`
+}
+
+// WriteSchema serializes types as versioned JSON, including their comments and the Go types they originate from.
+// Types and members keep their order, so that loading the schema yields the types as they were extracted. As extraction
+// is deterministic, the schema only changes if the API does.
+// Use ReadSchema to load it, e.g. to Render it in a separate step or to Diff it against a later version.
+func WriteSchema(out io.Writer, types []TypescriptType) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema{Version: SchemaVersion, Types: types})
+}
+
+// ReadSchema loads types serialized using WriteSchema. The types are ready to be rendered.
+func ReadSchema(in io.Reader) ([]TypescriptType, error) {
+	var res schema
+	err := json.NewDecoder(in).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d (expected %d)", res.Version, SchemaVersion)
+	}
+	for _, t := range res.Types {
+		if err := validateSchemaType(t); err != nil {
+			return nil, fmt.Errorf("%s: %v", t.Name, err)
+		}
+	}
+	return res.Types, nil
+}
+
+// validateSchemaType checks that a loaded type is well-formed
+func validateSchemaType(t TypescriptType) error {
+	switch t.Kind {
+	case TypescriptSimpleKind, TypescriptEnumKind, TypescriptUnionKind, TypescriptLiteralKind, "":
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return fmt.Errorf("array needs 1 type param")
+		}
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return fmt.Errorf("map needs 2 type params")
+		}
+	case TypescriptInterfaceKind:
+		for _, m := range t.Members {
+			if err := validateSchemaType(m.Type); err != nil {
+				return fmt.Errorf("%s: %v", m.Name, err)
+			}
+			for _, a := range m.Args {
+				if err := validateSchemaType(a.Type); err != nil {
+					return fmt.Errorf("%s: %v", m.Name, err)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", t.Kind)
+	}
+	for _, p := range t.Params {
+		if err := validateSchemaType(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package bel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestSchemaRoundtrip(t *testing.T) {
+	extract, err := Extract(NestedStruct{}, FollowStructs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reversed := make([]TypescriptType, len(extract))
+	for i, t := range extract {
+		reversed[len(extract)-1-i] = t
+	}
+
+	tests := []struct {
+		Name  string
+		Types []TypescriptType
+	}{
+		{"extraction", extract},
+		// neither types nor members are in alphabetical order
+		{"order", reversed},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteSchema(&buf, test.Types)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			act, err := ReadSchema(&buf)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if diff := deep.Equal(act, test.Types); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSchemaIsStable(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "B", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{
+			{TypedElement: TypedElement{Name: "z", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}},
+			{TypedElement: TypedElement{Name: "a", Type: TypescriptType{Name: "number", Kind: TypescriptSimpleKind}}},
+		}},
+		{Name: "A", Kind: TypescriptEnumKind, EnumMembers: []TypescriptEnumMember{{Name: "Two", Value: "2"}, {Name: "One", Value: "1"}}},
+	}
+
+	var a, b bytes.Buffer
+	if err := WriteSchema(&a, types); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := WriteSchema(&b, types); err != nil {
+		t.Error(err)
+		return
+	}
+	if a.String() != b.String() {
+		t.Errorf("schema is not deterministic:\n%s\n%s", a.String(), b.String())
+	}
+
+	expectation := `{
+  "version": 1,
+  "types": [
+    {
+      "name": "B",
+      "kind": "iface",
+      "members": [
+        {
+          "name": "z",
+          "type": {
+            "name": "string",
+            "kind": "simple"
+          }
+        },
+        {
+          "name": "a",
+          "type": {
+            "name": "number",
+            "kind": "simple"
+          }
+        }
+      ]
+    },
+    {
+      "name": "A",
+      "kind": "enum",
+      "enumMembers": [
+        {
+          "name": "Two",
+          "value": "2"
+        },
+        {
+          "name": "One",
+          "value": "1"
+        }
+      ]
+    }
+  ]
+}
+`
+	if a.String() != expectation {
+		t.Errorf("unexpected schema:\n%s", a.String())
+	}
+}
+
+func TestReadSchemaErrors(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Err   string
+	}{
+		{"no version", `{"types": []}`, "unsupported schema version 0 (expected 1)"},
+		{"newer version", `{"version": 2, "types": []}`, "unsupported schema version 2 (expected 1)"},
+		{"unknown kind", `{"version": 1, "types": [{"name": "Foo", "kind": "class"}]}`, `Foo: unknown kind "class"`},
+		{"unknown member kind", `{"version": 1, "types": [{"name": "Foo", "kind": "iface", "members": [{"name": "bar", "type": {"kind": "class"}}]}]}`, `Foo: bar: unknown kind "class"`},
+		{"array without params", `{"version": 1, "types": [{"name": "Foo", "kind": "array"}]}`, "Foo: array needs 1 type param"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := ReadSchema(strings.NewReader(test.Input))
+			if err == nil {
+				t.Errorf("expected error %q", test.Err)
+				return
+			}
+			if err.Error() != test.Err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderFromSchema(t *testing.T) {
+	extract, err := Extract(NestedStruct{}, FollowStructs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var schema bytes.Buffer
+	err = WriteSchema(&schema, extract)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	loaded, err := ReadSchema(&schema)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var exp, act bytes.Buffer
+	err = Render(extract, GenerateOutputTo(&exp), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = Render(loaded, GenerateOutputTo(&act), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if act.String() != exp.String() {
+		t.Errorf("rendering the schema differs from rendering the extraction:\n%s\n%s", act.String(), exp.String())
+	}
+}
@@ -0,0 +1,120 @@
+package bel
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// sourceScanner finds and parses the Go files of the packages in a directory tree, like the go tool would
+type sourceScanner struct {
+	fsys fs.FS
+	// root is the directory fsys refers to on disk, if any. Positions in parse errors are relative to it.
+	root  string
+	ctxt  build.Context
+	tests bool
+	mode  parser.Mode
+	fset  *token.FileSet
+}
+
+// newSourceScanner scans fsys. root is the directory fsys refers to on disk, or "" for other file systems (e.g. embed.FS).
+func newSourceScanner(fsys fs.FS, root string, mode parser.Mode) *sourceScanner {
+	return &sourceScanner{fsys: fsys, root: root, ctxt: build.Default, mode: mode, fset: token.NewFileSet()}
+}
+
+// newOSSourceScanner scans a directory on disk
+func newOSSourceScanner(dir string, mode parser.Mode) *sourceScanner {
+	return newSourceScanner(os.DirFS(dir), dir, mode)
+}
+
+// skipDir returns true for directories which do not contain packages of the module, e.g. vendor/ and testdata/
+func skipDir(name string) bool {
+	switch name {
+	case "vendor", "testdata", "node_modules":
+		return true
+	}
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// Walk parses the packages in dir and its subdirectories and calls fn for each directory with its files by package name.
+// Directories are slash-separated paths within the file system.
+func (s *sourceScanner) Walk(dir string, fn func(dir string, pkgs map[string][]*ast.File) error) error {
+	return fs.WalkDir(s.fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != dir && skipDir(d.Name()) {
+			return fs.SkipDir
+		}
+
+		pkgs, err := s.ParseDir(p)
+		if err != nil {
+			return err
+		}
+		if len(pkgs) == 0 {
+			return nil
+		}
+		return fn(p, pkgs)
+	})
+}
+
+// ParseDir parses the Go files in dir which match the build constraints of the scanner
+func (s *sourceScanner) ParseDir(dir string) (map[string][]*ast.File, error) {
+	entries, err := fs.ReadDir(s.fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxt := s.ctxt
+	ctxt.JoinPath = path.Join
+	ctxt.OpenFile = func(p string) (io.ReadCloser, error) { return s.fsys.Open(p) }
+
+	res := make(map[string][]*ast.File)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if !s.tests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		match, err := ctxt.MatchFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+
+		fn := path.Join(dir, name)
+		src, err := fs.ReadFile(s.fsys, fn)
+		if err != nil {
+			return nil, err
+		}
+		// parse errors carry the position of the offending code, e.g. foo/bar.go:3:1
+		f, err := parser.ParseFile(s.fset, s.filename(fn), src, s.mode)
+		if err != nil {
+			return nil, err
+		}
+		res[f.Name.Name] = append(res[f.Name.Name], f)
+	}
+	return res, nil
+}
+
+// filename returns the name of a file for use in positions
+func (s *sourceScanner) filename(fn string) string {
+	if s.root == "" {
+		return fn
+	}
+	return filepath.Join(s.root, filepath.FromSlash(fn))
+}
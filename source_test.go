@@ -0,0 +1,137 @@
+package bel
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-test/deep"
+)
+
+func TestSourceScanning(t *testing.T) {
+	dir := t.TempDir()
+	enum := func(pkg, name string) string {
+		return "package " + pkg + "\n\ntype " + name + " string\n\nconst " + name + "Value " + name + " = \"value\"\n"
+	}
+	err := writeTestFiles(dir, map[string]string{
+		"go.mod":                 "module example.com/app\n",
+		"app.go":                 enum("app", "State"),
+		"app_linux.go":           enum("app", "Linux"),
+		"tagged.go":              "//go:build special\n\n" + enum("app", "Tagged"),
+		"app_test.go":            enum("app", "InTest"),
+		"sub/sub.go":             enum("sub", "Sub"),
+		"vendor/dep/dep.go":      enum("dep", "Vendored"),
+		"testdata/data.go":       enum("data", "TestData"),
+		"node_modules/js/js.go":  enum("js", "NodeModule"),
+		".hidden/hidden.go":      enum("hidden", "Hidden"),
+		"_ignored/ignored.go":    enum("ignored", "Ignored"),
+		"sub/testdata/nested.go": enum("nested", "NestedTestData"),
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		Name        string
+		Options     []ParsedSourceEnumOption
+		Expectation []string
+	}{
+		{"linux", []ParsedSourceEnumOption{BuildConstraints("linux", "amd64")}, []string{"example.com/app.Linux", "example.com/app.State", "example.com/app/sub.Sub"}},
+		{"tags", []ParsedSourceEnumOption{BuildConstraints("darwin", "arm64", "special")}, []string{"example.com/app.State", "example.com/app.Tagged", "example.com/app/sub.Sub"}},
+		{"tests", []ParsedSourceEnumOption{BuildConstraints("linux", "amd64"), IncludeTestFiles}, []string{"example.com/app.InTest", "example.com/app.Linux", "example.com/app.State", "example.com/app/sub.Sub"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handler, err := NewParsedSourceEnumHandler(dir, test.Options...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var act []string
+			for k := range handler.enums {
+				act = append(act, k)
+			}
+			sort.Strings(act)
+			if diff := deep.Equal(act, test.Expectation); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSourceScanningErrors(t *testing.T) {
+	dir := t.TempDir()
+	err := writeTestFiles(dir, map[string]string{
+		"bad/bad.go": "package bad\n\nfunc {\n",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	_, err = NewParsedSourceEnumHandler(dir)
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, "bad", "bad.go")+":3:") {
+		t.Errorf("expected positioned parse error, got %v", err)
+	}
+
+	_, err = NewParsedSourceEnumHandler(filepath.Join(dir, "does-not-exist"))
+	if err == nil {
+		t.Errorf("expected error for missing directory")
+	}
+}
+
+func TestSourceScanningFS(t *testing.T) {
+	src := func(pkg, content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("package " + pkg + "\n\n" + content)}
+	}
+	fsys := fstest.MapFS{
+		"mod/go.mod":           {Data: []byte("module example.com/mod\n")},
+		"mod/api/api.go":       src("api", "type State string\n\nconst Open State = \"open\"\n"),
+		"nomod/api.go":         src("api", "type State string\n\nconst Closed State = \"closed\"\n"),
+		"nomod/vendor/dep.go":  src("dep", "type Vendored string\n\nconst V Vendored = \"v\"\n"),
+		"docs/embedded_doc.go": src("bel", "// EmbeddedDocStruct is documented in embedded sources\ntype EmbeddedDocStruct struct{}\n"),
+	}
+
+	tests := []struct {
+		Name        string
+		Root        string
+		Options     []ParsedSourceEnumOption
+		Expectation []string
+	}{
+		{"go.mod", "mod", nil, []string{"example.com/mod/api.State"}},
+		{"module path", "nomod", []ParsedSourceEnumOption{ModulePath("example.com/nomod")}, []string{"example.com/nomod.State"}},
+		{"unknown module", "nomod", nil, []string{"State"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handler, err := NewParsedSourceEnumHandlerFS(fsys, test.Root, test.Options...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var act []string
+			for k := range handler.enums {
+				act = append(act, k)
+			}
+			sort.Strings(act)
+			if diff := deep.Equal(act, test.Expectation); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	docs, err := NewParsedSourceDocHandlerFS(fsys, "docs", "github.com/32leaves/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if doc := docs.Type(reflect.TypeOf(EmbeddedDocStruct{})); doc != "EmbeddedDocStruct is documented in embedded sources" {
+		t.Errorf("unexpected documentation: %q", doc)
+	}
+}
+
+type EmbeddedDocStruct struct{}
@@ -0,0 +1,143 @@
+package bel
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// SumTypeVariant is a struct implementing a sum type interface, along with the value of the
+// discriminator field which identifies it
+type SumTypeVariant struct {
+	Value string
+	Type  interface{}
+}
+
+// Variant declares a variant of a sum type, see WithSumType
+func Variant(value string, v interface{}) SumTypeVariant {
+	return SumTypeVariant{Value: value, Type: v}
+}
+
+// sumType is a Go interface which is a sum type of structs, distinguished by a discriminator field
+type sumType struct {
+	iface         reflect.Type
+	discriminator string
+	variants      []sumTypeVariant
+}
+
+type sumTypeVariant struct {
+	value string
+	t     reflect.Type
+}
+
+// WithSumType declares that a Go interface (e.g. `(*Shape)(nil)`) is a sum type of the given variants,
+// which are serialized with a discriminator field (e.g. "kind"). Such interfaces are extracted as a union of
+// their variants (`export type Shape = Circle | Square`), and each variant gets a discriminator member whose
+// type is the literal value of the variant (e.g. `kind: "circle"`).
+func WithSumType(iface interface{}, discriminator string, variants ...SumTypeVariant) ExtractOption {
+	return func(e *extractor) {
+		st := sumType{iface: derefType(reflect.TypeOf(iface)), discriminator: discriminator}
+		for _, v := range variants {
+			st.variants = append(st.variants, sumTypeVariant{value: v.Value, t: derefType(reflect.TypeOf(v.Type))})
+		}
+		e.sumTypeDecls = append(e.sumTypeDecls, st)
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// registerSumTypes validates the sum types declared using WithSumType and indexes them
+func (e *extractor) registerSumTypes() error {
+	e.sumTypes = make(map[reflect.Type]*sumType)
+	e.variants = make(map[reflect.Type]TypescriptMember)
+	for i := range e.sumTypeDecls {
+		st := &e.sumTypeDecls[i]
+		if st.iface == nil || st.iface.Kind() != reflect.Interface {
+			return fmt.Errorf("sum type %v is not an interface", st.iface)
+		}
+		if st.discriminator == "" {
+			return fmt.Errorf("sum type %s has no discriminator", st.iface.Name())
+		}
+		if _, exists := e.sumTypes[st.iface]; exists {
+			return fmt.Errorf("sum type %s is declared twice", st.iface.Name())
+		}
+		e.sumTypes[st.iface] = st
+
+		for _, v := range st.variants {
+			if v.t == nil || v.t.Kind() != reflect.Struct {
+				return fmt.Errorf("variant %v of sum type %s is not a struct", v.t, st.iface.Name())
+			}
+			if !v.t.Implements(st.iface) && !reflect.PtrTo(v.t).Implements(st.iface) {
+				return fmt.Errorf("variant %s does not implement sum type %s", v.t.Name(), st.iface.Name())
+			}
+			if _, exists := e.variants[v.t]; exists {
+				return fmt.Errorf("%s is a variant of more than one sum type", v.t.Name())
+			}
+			e.variants[v.t] = TypescriptMember{
+				TypedElement: TypedElement{
+					Name: st.discriminator,
+					Type: TypescriptType{Name: strconv.Quote(v.value), Kind: TypescriptLiteralKind},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// extractSumType produces the union of a sum type's variants. The variants are embedded if we embed structs,
+// and referred to by name (and extracted if we follow structs) otherwise.
+func (e *extractor) extractSumType(st *sumType) (*TypescriptType, error) {
+	res := &TypescriptType{
+		Name:          e.typeNamer(st.iface),
+		Comment:       e.docHandler.Type(st.iface),
+		Kind:          TypescriptUnionKind,
+		PkgPath:       st.iface.PkgPath(),
+		GoName:        st.iface.Name(),
+		Discriminator: st.discriminator,
+	}
+	for _, v := range st.variants {
+		if e.embedStructs {
+			if e.visiting[v.t] {
+				return nil, fmt.Errorf("cannot embed recursive struct %s", v.t.Name())
+			}
+			vt, err := e.extractStruct(v.t)
+			if err != nil {
+				return nil, err
+			}
+			vt.Name, vt.PkgPath, vt.GoName = "", "", ""
+			res.Params = append(res.Params, *vt)
+			continue
+		}
+
+		if e.followStructs && !e.visiting[v.t] {
+			vt, err := e.extractStruct(v.t)
+			if err != nil {
+				return nil, err
+			}
+			e.addResult(vt)
+		}
+		res.Params = append(res.Params, TypescriptType{Name: e.typeNamer(v.t), Kind: TypescriptSimpleKind})
+	}
+	return res, nil
+}
+
+// withDiscriminator adds the discriminator member to the fields of a sum type variant, replacing a field of the same name
+func (e *extractor) withDiscriminator(t reflect.Type, fields []TypescriptMember) []TypescriptMember {
+	disc, ok := e.variants[t]
+	if !ok {
+		return fields
+	}
+	for i, f := range fields {
+		if f.Name == disc.Name {
+			disc.Comment = f.Comment
+			fields[i] = disc
+			return fields
+		}
+	}
+	return append([]TypescriptMember{disc}, fields...)
+}
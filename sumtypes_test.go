@@ -0,0 +1,182 @@
+package bel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type Shape interface {
+	isShape()
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (Circle) isShape() {}
+
+type Square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (*Square) isShape() {}
+
+type Drawing struct {
+	Shapes     []Shape `json:"shapes"`
+	Background Shape   `json:"background,omitempty"`
+}
+
+var shapeSumType = WithSumType((*Shape)(nil), "kind", Variant("circle", Circle{}), Variant("square", Square{}))
+
+func TestExtractSumType(t *testing.T) {
+	extract, err := Extract(Drawing{}, shapeSumType, FollowStructs, SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	pkg := "github.com/32leaves/bel"
+	kind := func(value string) TypescriptMember {
+		return TypescriptMember{TypedElement: TypedElement{Name: "kind", Type: TypescriptType{Name: value, Kind: TypescriptLiteralKind}}}
+	}
+	number := TypescriptType{Name: "number", Kind: TypescriptSimpleKind, Format: "double"}
+	shape := TypescriptType{Name: "Shape", Kind: TypescriptSimpleKind}
+	expectation := []TypescriptType{
+		{
+			Name:    "Circle",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: pkg,
+			GoName:  "Circle",
+			Members: []TypescriptMember{
+				kind(`"circle"`),
+				{TypedElement: TypedElement{Name: "radius", Type: number}},
+			},
+		},
+		{
+			Name:    "Drawing",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: pkg,
+			GoName:  "Drawing",
+			Members: []TypescriptMember{
+				{TypedElement: TypedElement{Name: "background", Type: shape}, IsOptional: true},
+				{TypedElement: TypedElement{Name: "shapes", Type: TypescriptType{Kind: TypescriptArrayKind, Params: []TypescriptType{shape}}}},
+			},
+		},
+		{
+			Name:          "Shape",
+			Kind:          TypescriptUnionKind,
+			PkgPath:       pkg,
+			GoName:        "Shape",
+			Discriminator: "kind",
+			Params: []TypescriptType{
+				{Name: "Circle", Kind: TypescriptSimpleKind},
+				{Name: "Square", Kind: TypescriptSimpleKind},
+			},
+		},
+		{
+			Name:    "Square",
+			Kind:    TypescriptInterfaceKind,
+			PkgPath: pkg,
+			GoName:  "Square",
+			Members: []TypescriptMember{
+				kind(`"square"`),
+				{TypedElement: TypedElement{Name: "side", Type: number}},
+			},
+		},
+	}
+	if diff := deep.Equal(extract, expectation); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRenderSumType(t *testing.T) {
+	extract, err := Extract((*Shape)(nil), shapeSumType, EmbedStructs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var out bytes.Buffer
+	err = Render(extract, GeneratePreamble(""), GenerateOutputTo(&out))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expectation := `export type Shape =
+    {
+        kind: "circle"
+        radius: number
+    } | {
+        kind: "square"
+        side: number
+    };
+`
+	if out.String() != expectation {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	extract, err = Extract(Drawing{}, shapeSumType, FollowStructs, SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	tests := []struct {
+		Name        string
+		Options     []GenerateOption
+		Expectation []string
+	}{
+		{"typescript", nil, []string{"export type Shape =\n    Circle | Square;\n", "    kind: \"circle\"\n"}},
+		{"template", []GenerateOption{GenerateUsing(TemplateRenderer)}, []string{"export type Shape =\n    Circle | Square;\n", "    kind: \"circle\"\n"}},
+		{"guards", []GenerateOption{GenerateTypeGuards}, []string{"return (isCircle(v) || isSquare(v));", `["kind"] === "circle"`}},
+		{"classes", []GenerateOption{GenerateClasses}, []string{`(obj["background"]["kind"] === "circle" ? Circle.fromJSON(obj["background"]) : obj["background"]["kind"] === "square" ? Square.fromJSON(obj["background"]) : obj["background"])`}},
+		{"zod", []GenerateOption{GenerateUsing(ZodRenderer)}, []string{`export const Shape = z.discriminatedUnion("kind", [Circle, Square]);`, `kind: z.literal("circle"),`}},
+		{"io-ts", []GenerateOption{GenerateUsing(IoTsRenderer)}, []string{"export const Shape = t.union([Circle, Square]);", `kind: t.literal("circle"),`}},
+		{"openapi", []GenerateOption{GenerateUsing(OpenAPIRenderer), GenerateJSON}, []string{`"oneOf": [`, `"propertyName": "kind"`, `"enum": [
+              "circle"
+            ]`}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Render(extract, append(test.Options, GeneratePreamble(""), GenerateOutputTo(&out))...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, exp := range test.Expectation {
+				if !strings.Contains(out.String(), exp) {
+					t.Errorf("output does not contain %q:\n%s", exp, out.String())
+				}
+			}
+		})
+	}
+}
+
+type NotAShape struct{}
+
+func TestSumTypeErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Option ExtractOption
+		Err    string
+	}{
+		{"not an interface", WithSumType(Circle{}, "kind"), "sum type bel.Circle is not an interface"},
+		{"no discriminator", WithSumType((*Shape)(nil), ""), "sum type Shape has no discriminator"},
+		{"not implemented", WithSumType((*Shape)(nil), "kind", Variant("nope", NotAShape{})), "variant NotAShape does not implement sum type Shape"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := Extract(Drawing{}, test.Option)
+			if err == nil {
+				t.Errorf("expected error %q", test.Err)
+				return
+			}
+			if err.Error() != test.Err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -1,5 +1,12 @@
 package bel
 
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 // TypescriptKind is the kind of a Typescript type (akin to the kind of Go types)
 type TypescriptKind string
 
@@ -14,36 +21,171 @@ const (
 	TypescriptInterfaceKind TypescriptKind = "iface"
 	// TypescriptEnumKind means the type is an enum
 	TypescriptEnumKind TypescriptKind = "enum"
+	// TypescriptUnionKind means the type is a union of its params, e.g. the variants of a sum type
+	TypescriptUnionKind TypescriptKind = "union"
+	// TypescriptLiteralKind means the type is a literal, e.g. "circle". Its name is the literal.
+	TypescriptLiteralKind TypescriptKind = "literal"
 )
 
-// TypescriptType describes a type in the Typescript world
+// TypescriptType describes a type in the Typescript world.
+// PkgPath and GoName identify the Go type it originates from, if it's a named type.
+// Format carries details of the Go type lost in the Typescript type, e.g. int64 or date-time,
+// and IsNullable is set for pointers, which marshal to JSON null if nil. Nil slices and maps marshal to
+// null as well, but aren't marked nullable: most APIs treat them as empty rather than absent.
+// Discriminator names the member which distinguishes the variants of a union, if any.
 type TypescriptType struct {
-	Name        string
-	Comment     string
-	Kind        TypescriptKind
-	Members     []TypescriptMember
-	Params      []TypescriptType
-	EnumMembers []TypescriptEnumMember
+	Name          string                 `json:"name,omitempty"`
+	Comment       string                 `json:"comment,omitempty"`
+	Kind          TypescriptKind         `json:"kind"`
+	PkgPath       string                 `json:"pkgPath,omitempty"`
+	GoName        string                 `json:"goName,omitempty"`
+	Format        string                 `json:"format,omitempty"`
+	IsNullable    bool                   `json:"nullable,omitempty"`
+	Members       []TypescriptMember     `json:"members,omitempty"`
+	Params        []TypescriptType       `json:"params,omitempty"`
+	EnumMembers   []TypescriptEnumMember `json:"enumMembers,omitempty"`
+	Discriminator string                 `json:"discriminator,omitempty"`
 }
 
 // TypescriptMember is a member of a Typescript interface
 type TypescriptMember struct {
 	TypedElement
-	Comment    string
-	IsOptional bool
-	IsFunction bool
-	Args       []TypedElement
+	Comment    string         `json:"comment,omitempty"`
+	IsOptional bool           `json:"optional,omitempty"`
+	IsFunction bool           `json:"function,omitempty"`
+	Args       []TypedElement `json:"args,omitempty"`
 }
 
 // TypescriptEnumMember is a member of a Typescript enum
 type TypescriptEnumMember struct {
-	Name    string
-	Value   string
-	Comment string
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// isIntegerFormat returns true if a number of that format is an integer
+func isIntegerFormat(format string) bool {
+	switch format {
+	case "int32", "int64", "uint64":
+		return true
+	}
+	return false
 }
 
 // TypedElement pairs a name with a type
 type TypedElement struct {
-	Name string
-	Type TypescriptType
+	Name string         `json:"name"`
+	Type TypescriptType `json:"type"`
+}
+
+// typeExpr produces the Typescript type expression for t, e.g. to type a member or parameter
+func typeExpr(t TypescriptType) string {
+	var res string
+	switch t.Kind {
+	case TypescriptArrayKind:
+		elem := "unknown"
+		if len(t.Params) == 1 {
+			elem = typeExpr(t.Params[0])
+		}
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		res = elem + "[]"
+	case TypescriptMapKind:
+		key, val := "string", "unknown"
+		if len(t.Params) == 2 {
+			key, val = typeExpr(t.Params[0]), typeExpr(t.Params[1])
+		}
+		res = fmt.Sprintf("{ [key: %s]: %s }", key, val)
+	case TypescriptUnionKind:
+		variants := make([]string, len(t.Params))
+		for i, p := range t.Params {
+			variants[i] = typeExpr(p)
+		}
+		res = strings.Join(variants, " | ")
+		if len(variants) == 0 {
+			res = "never"
+		}
+	case TypescriptInterfaceKind:
+		members := make([]string, len(t.Members))
+		for i, m := range t.Members {
+			members[i] = memberExpr(m)
+		}
+		res = "{ " + strings.Join(members, "; ") + " }"
+		if len(members) == 0 {
+			res = "{}"
+		}
+	default:
+		res = t.Name
+	}
+	if res == "" {
+		res = "void"
+	}
+
+	if t.IsNullable {
+		res += " | null"
+	}
+	return res
+}
+
+// interfaceTypeExpr produces the type expression for t as used by the interfaces of the Typescript renderers,
+// which do not mark nullable types. Classes implementing those interfaces must use it to agree with them.
+func interfaceTypeExpr(t TypescriptType) string {
+	return typeExpr(withoutNullable(t))
+}
+
+// withoutNullable clears IsNullable of t and of the types it is composed of
+func withoutNullable(t TypescriptType) TypescriptType {
+	t.IsNullable = false
+	if len(t.Params) > 0 {
+		params := make([]TypescriptType, len(t.Params))
+		for i, p := range t.Params {
+			params[i] = withoutNullable(p)
+		}
+		t.Params = params
+	}
+	if len(t.Members) > 0 {
+		members := make([]TypescriptMember, len(t.Members))
+		for i, m := range t.Members {
+			m.Type = withoutNullable(m.Type)
+			if len(m.Args) > 0 {
+				args := make([]TypedElement, len(m.Args))
+				for j, a := range m.Args {
+					args[j] = TypedElement{Name: a.Name, Type: withoutNullable(a.Type)}
+				}
+				m.Args = args
+			}
+			members[i] = m
+		}
+		t.Members = members
+	}
+	return t
+}
+
+// memberExpr produces the Typescript declaration of an interface member
+func memberExpr(m TypescriptMember) string {
+	var opt string
+	if m.IsOptional {
+		opt = "?"
+	}
+	if !m.IsFunction {
+		return fmt.Sprintf("%s%s: %s", propertyName(m.Name), opt, typeExpr(m.Type))
+	}
+
+	args := make([]string, len(m.Args))
+	for i, a := range m.Args {
+		args[i] = fmt.Sprintf("%s: %s", a.Name, typeExpr(a.Type))
+	}
+	return fmt.Sprintf("%s%s(%s): %s", m.Name, opt, strings.Join(args, ", "), typeExpr(m.Type))
+}
+
+// identifierPattern matches valid Typescript identifiers (ASCII only)
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyName quotes a property name if it's not a valid identifier, e.g. because it contains a dash
+func propertyName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
 }
@@ -0,0 +1,140 @@
+package bel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// yamlField is a key/value pair of a YAML mapping. We keep fields in a slice to retain their order.
+type yamlField struct {
+	Key   string
+	Value interface{}
+}
+
+// yamlPlainKey matches keys which need no quotes, unless they're a literal (see yamlLiterals)
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+// yamlLiterals are the (lower case) plain scalars YAML 1.1 reads as booleans or null, rather than strings
+var yamlLiterals = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+	"true": true, "false": true, "null": true, "~": true,
+}
+
+// jsonToYAML writes a JSON document as block-style YAML, retaining the order of object keys
+func jsonToYAML(out io.Writer, fc []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(fc))
+	dec.UseNumber()
+	doc, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writeYAML(&buf, doc, 0)
+	_, err = buf.WriteTo(out)
+	return err
+}
+
+func decodeOrderedJSON(dec *json.Decoder) (interface{}, error) {
+	tkn, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tkn {
+	case json.Delim('{'):
+		res := make([]yamlField, 0)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, yamlField{Key: fmt.Sprint(key), Value: val})
+		}
+		_, err = dec.Token()
+		return res, err
+	case json.Delim('['):
+		res := make([]interface{}, 0)
+		for dec.More() {
+			val, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, val)
+		}
+		_, err = dec.Token()
+		return res, err
+	}
+	return tkn, nil
+}
+
+func writeYAML(out *bytes.Buffer, val interface{}, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch v := val.(type) {
+	case []yamlField:
+		for _, f := range v {
+			key := f.Key
+			if !yamlPlainKey.MatchString(key) || yamlLiterals[strings.ToLower(key)] {
+				key = yamlScalar(key)
+			}
+			out.WriteString(indent + key + ":")
+			writeYAMLValue(out, f.Value, depth)
+		}
+	case []interface{}:
+		for _, e := range v {
+			if m, ok := e.([]yamlField); ok && len(m) > 0 {
+				// compact notation: the first field of the mapping follows the dash
+				var buf bytes.Buffer
+				writeYAML(&buf, m, depth+1)
+				out.WriteString(indent + "- ")
+				out.Write(buf.Bytes()[len(indent)+2:])
+				continue
+			}
+
+			out.WriteString(indent + "-")
+			writeYAMLValue(out, e, depth)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a mapping field or sequence element
+func writeYAMLValue(out *bytes.Buffer, val interface{}, depth int) {
+	switch v := val.(type) {
+	case []yamlField:
+		if len(v) == 0 {
+			out.WriteString(" {}\n")
+			return
+		}
+		out.WriteString("\n")
+		writeYAML(out, v, depth+1)
+	case []interface{}:
+		if len(v) == 0 {
+			out.WriteString(" []\n")
+			return
+		}
+		out.WriteString("\n")
+		writeYAML(out, v, depth+1)
+	default:
+		out.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlScalar(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		// JSON strings are valid double-quoted YAML scalars
+		fc, _ := json.Marshal(v)
+		return string(fc)
+	}
+	return fmt.Sprint(val)
+}
@@ -0,0 +1,38 @@
+package bel
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONToYAML(t *testing.T) {
+	tests := []struct {
+		Name        string
+		JSON        string
+		Expectation string
+	}{
+		{"order", `{"b": 1, "a": true, "c": null}`, "b: 1\na: true\nc: null\n"},
+		{"key with colon", `{"a:b": 1, "a b": 2}`, "\"a:b\": 1\n\"a b\": 2\n"},
+		{"numeric key", `{"200": {"description": "ok"}}`, "\"200\":\n  description: \"ok\"\n"},
+		{"multi-line string", `{"description": "first line\nsecond: line"}`, "description: \"first line\\nsecond: line\"\n"},
+		{"special strings", `{"a": "true", "b": "- x # y", "c": ""}`, "a: \"true\"\nb: \"- x # y\"\nc: \"\"\n"},
+		{"literal keys", `{"y": 1, "No": 2, "ON": 3, "off": 4, "true": 5, "null": 6, "~": 7, "yesterday": 8}`,
+			"\"y\": 1\n\"No\": 2\n\"ON\": 3\n\"off\": 4\n\"true\": 5\n\"null\": 6\n\"~\": 7\nyesterday: 8\n"},
+		{"empty map", `{"properties": {}, "items": []}`, "properties: {}\nitems: []\n"},
+		{"nested", `{"a": {"b": {"c": 1.5}}}`, "a:\n  b:\n    c: 1.5\n"},
+		{"sequence of maps", `{"allOf": [{"a": 1, "b": 2}, {}, [1], "x"]}`, "allOf:\n  - a: 1\n    b: 2\n  - {}\n  -\n    - 1\n  - \"x\"\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := jsonToYAML(&out, []byte(test.JSON))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if out.String() != test.Expectation {
+				t.Errorf("unexpected YAML:\n%s", out.String())
+			}
+		})
+	}
+}
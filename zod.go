@@ -0,0 +1,233 @@
+package bel
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// zodRenderer produces Zod schemas for a set of types
+type zodRenderer struct {
+	known     map[string]bool
+	recursive map[string]bool
+	// declared contains all types whose schema we've produced already
+	declared map[string]bool
+}
+
+// RenderZod produces a Zod schema (`export const Foo = z.object({...})`) and its inferred
+// type (`export type Foo = z.infer<typeof Foo>`) for each of the types. Schemas are ordered such that they can refer to each other. Recursive types are
+// declared using z.lazy and an explicit type.
+func RenderZod(types []TypescriptType, cfg ...GenerateOption) error {
+	return renderZod(types, newGenerateOptions(cfg))
+}
+
+func renderZod(types []TypescriptType, opts GenerateOptions) error {
+	ordered, recursive := dependencyOrder(types)
+	r := &zodRenderer{
+		known:     make(map[string]bool),
+		recursive: recursive,
+		declared:  make(map[string]bool),
+	}
+
+	for _, t := range types {
+		r.known[t.Name] = true
+	}
+
+	var out strings.Builder
+	out.WriteString(opts.Preamble)
+	out.WriteString("import { z } from \"zod\";\n")
+	for _, t := range ordered {
+		if isServiceInterface(t) {
+			continue
+		}
+
+		decl, err := r.declaration(t)
+		if err != nil {
+			return fmt.Errorf("cannot produce Zod schema for %s: %v", t.Name, err)
+		}
+		out.WriteString("\n")
+		out.WriteString(decl)
+		r.declared[t.Name] = true
+	}
+
+	_, err := io.WriteString(opts.Out, out.String())
+	return err
+}
+
+func (r *zodRenderer) declaration(t TypescriptType) (string, error) {
+	schema, err := r.schema(t)
+	if err != nil {
+		return "", err
+	}
+
+	var res strings.Builder
+	res.WriteString(docComment(t.Comment, 0))
+	if r.recursive[t.Name] {
+		// Zod cannot infer the type of recursive schemas
+		fmt.Fprintf(&res, "export type %s = %s;\n", t.Name, typeExpr(declaredType(t)))
+		fmt.Fprintf(&res, "export const %s: z.ZodType<%s> = z.lazy(() => %s);\n", t.Name, t.Name, schema)
+		return res.String(), nil
+	}
+
+	fmt.Fprintf(&res, "export const %s = %s;\n", t.Name, schema)
+	fmt.Fprintf(&res, "export type %s = z.infer<typeof %s>;\n", t.Name, t.Name)
+	return res.String(), nil
+}
+
+func (r *zodRenderer) schema(t TypescriptType) (string, error) {
+	res, err := r.nonNullSchema(t)
+	if err != nil {
+		return "", err
+	}
+	if t.IsNullable {
+		res += ".nullable()"
+	}
+	return res, nil
+}
+
+func (r *zodRenderer) nonNullSchema(t TypescriptType) (string, error) {
+	switch t.Kind {
+	case TypescriptSimpleKind:
+		switch t.Name {
+		case "string":
+			if t.Format == "date-time" {
+				return "z.string().datetime({ offset: true })", nil
+			}
+			return "z.string()", nil
+		case "number":
+			if isIntegerFormat(t.Format) {
+				return "z.number().int()", nil
+			}
+			return "z.number()", nil
+		case "boolean":
+			return "z.boolean()", nil
+		}
+		if !r.known[t.Name] {
+			return "", fmt.Errorf("unknown type %s - consider extracting with FollowStructs", t.Name)
+		}
+		if !r.declared[t.Name] {
+			return fmt.Sprintf("z.lazy(() => %s)", t.Name), nil
+		}
+		return t.Name, nil
+	case TypescriptArrayKind:
+		if len(t.Params) != 1 {
+			return "", fmt.Errorf("array needs 1 type param")
+		}
+		elem, err := r.schema(t.Params[0])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("z.array(%s)", elem), nil
+	case TypescriptMapKind:
+		if len(t.Params) != 2 {
+			return "", fmt.Errorf("map needs 2 type params")
+		}
+		// JSON object keys are always strings, unless they're constrained by an enum
+		key := "z.string()"
+		if k := t.Params[0]; k.Kind == TypescriptSimpleKind && k.Name != "string" && k.Name != "number" {
+			var err error
+			if key, err = r.schema(k); err != nil {
+				return "", err
+			}
+		}
+		val, err := r.schema(t.Params[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("z.record(%s, %s)", key, val), nil
+	case TypescriptEnumKind:
+		return zodEnum(t.EnumMembers), nil
+	case TypescriptLiteralKind:
+		return fmt.Sprintf("z.literal(%s)", t.Name), nil
+	case TypescriptUnionKind:
+		return r.union(t)
+	case TypescriptInterfaceKind:
+		var res strings.Builder
+		res.WriteString("z.object({")
+		for _, m := range t.Members {
+			if m.IsFunction {
+				continue
+			}
+
+			s, err := r.schema(m.Type)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", m.Name, err)
+			}
+			if m.IsOptional {
+				s += ".optional()"
+			}
+			fmt.Fprintf(&res, "\n    %s: %s,", propertyName(m.Name), strings.Replace(s, "\n", "\n    ", -1))
+		}
+		if len(t.Members) > 0 {
+			res.WriteString("\n")
+		}
+		res.WriteString("})")
+		return res.String(), nil
+	}
+	return "", fmt.Errorf("unsupported kind %s", t.Kind)
+}
+
+// union produces z.discriminatedUnion if possible, i.e. if all variants are declared objects, and z.union otherwise
+func (r *zodRenderer) union(t TypescriptType) (string, error) {
+	variants := make([]string, len(t.Params))
+	discriminated := t.Discriminator != ""
+	for i, p := range t.Params {
+		s, err := r.schema(p)
+		if err != nil {
+			return "", err
+		}
+		variants[i] = s
+		discriminated = discriminated && !p.IsNullable && (p.Kind == TypescriptInterfaceKind || (p.Kind == TypescriptSimpleKind && r.declared[p.Name] && !r.recursive[p.Name]))
+	}
+
+	switch {
+	case len(variants) == 0:
+		return "z.never()", nil
+	case len(variants) == 1:
+		return variants[0], nil
+	case discriminated:
+		return fmt.Sprintf("z.discriminatedUnion(%q, [%s])", t.Discriminator, strings.Join(variants, ", ")), nil
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(variants, ", ")), nil
+}
+
+// zodEnum produces z.enum for string enums, and a union of literals otherwise
+func zodEnum(members []TypescriptEnumMember) string {
+	values := make([]string, len(members))
+	allStrings := true
+	for i, m := range members {
+		values[i] = m.Value
+		if _, err := strconv.Unquote(m.Value); err != nil {
+			allStrings = false
+		}
+	}
+
+	if len(values) == 0 {
+		return "z.never()"
+	}
+	if allStrings {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(values, ", "))
+	}
+	if len(values) == 1 {
+		return fmt.Sprintf("z.literal(%s)", values[0])
+	}
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = fmt.Sprintf("z.literal(%s)", v)
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+}
+
+// declaredType returns the type a declaration of t refers to. Enums are turned into a union of their values.
+func declaredType(t TypescriptType) TypescriptType {
+	if t.Kind != TypescriptEnumKind {
+		return t
+	}
+
+	values := make([]string, len(t.EnumMembers))
+	for i, m := range t.EnumMembers {
+		values[i] = m.Value
+	}
+	return TypescriptType{Kind: TypescriptSimpleKind, Name: strings.Join(values, " | ")}
+}
@@ -0,0 +1,134 @@
+package bel
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ZodTree refers to itself
+type ZodTree struct {
+	Label    MyEnum             `json:"label"`
+	Children []ZodTree          `json:"children,omitempty"`
+	Leaf     *AnotherTestStruct `json:"leaf"`
+	Weights  map[string]float64 `json:"weights"`
+}
+
+func TestRenderZod(t *testing.T) {
+	handler, err := NewParsedSourceEnumHandler(".", IncludeTestFiles)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extract, err := Extract(ZodTree{}, FollowStructs, WithEnumerations(handler), SortAlphabetically)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderZod(extract, GenerateOutputTo(&out), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := `import { z } from "zod";
+
+export const AnotherTestStruct = z.object({
+    Bar: z.boolean(),
+    Foo: z.string(),
+});
+export type AnotherTestStruct = z.infer<typeof AnotherTestStruct>;
+
+export const MyEnum = z.enum(["member-one", "member-two", "member-three"]);
+export type MyEnum = z.infer<typeof MyEnum>;
+
+export type ZodTree = { children?: ZodTree[]; label: MyEnum; leaf: AnotherTestStruct | null; weights: { [key: string]: number } };
+export const ZodTree: z.ZodType<ZodTree> = z.lazy(() => z.object({
+    children: z.array(z.lazy(() => ZodTree)).optional(),
+    label: MyEnum,
+    leaf: AnotherTestStruct.nullable(),
+    weights: z.record(z.string(), z.number()),
+}));
+`
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected Zod schema:\n%s", act)
+	}
+}
+
+func TestRenderZodIntegers(t *testing.T) {
+	extract, err := Extract(Counters{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var out bytes.Buffer
+	err = RenderZod(extract, GenerateOutputTo(&out), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := `import { z } from "zod";
+
+export const Counters = z.object({
+    hits: z.number().int(),
+    total: z.number().int(),
+    offset: z.number().int(),
+    ratio: z.number(),
+});
+export type Counters = z.infer<typeof Counters>;
+`
+	if act := out.String(); act != expectation {
+		t.Errorf("unexpected Zod schema:\n%s", act)
+	}
+}
+
+func TestRenderZodComments(t *testing.T) {
+	types := []TypescriptType{
+		{Name: "Note", Kind: TypescriptInterfaceKind, Comment: "Note is documented\n\nacross several lines", Members: []TypescriptMember{
+			{TypedElement: TypedElement{Name: "text", Type: TypescriptType{Name: "string", Kind: TypescriptSimpleKind}}},
+		}},
+	}
+
+	var out bytes.Buffer
+	err := RenderZod(types, GenerateOutputTo(&out), GeneratePreamble(""))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectation := "/**\n * Note is documented\n *\n * across several lines\n */\nexport const Note = z.object({"
+	if act := out.String(); !strings.Contains(act, expectation) {
+		t.Errorf("unexpected Zod schema:\n%s", act)
+	}
+}
+
+func TestDependencyOrder(t *testing.T) {
+	ref := func(name string) TypescriptMember {
+		return TypescriptMember{TypedElement: TypedElement{Name: name, Type: TypescriptType{Name: name, Kind: TypescriptSimpleKind}}}
+	}
+	types := []TypescriptType{
+		{Name: "A", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{ref("B"), ref("C")}},
+		{Name: "B", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{ref("C")}},
+		{Name: "C", Kind: TypescriptInterfaceKind},
+		{Name: "D", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{ref("E")}},
+		{Name: "E", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{ref("D"), ref("C")}},
+		{Name: "F", Kind: TypescriptInterfaceKind, Members: []TypescriptMember{ref("F")}},
+	}
+
+	ordered, recursive := dependencyOrder(types)
+	var names []string
+	for _, t := range ordered {
+		names = append(names, t.Name)
+	}
+	if act := strings.Join(names, ","); act != "C,B,A,D,E,F" {
+		t.Errorf("unexpected order: %s", act)
+	}
+	if !reflect.DeepEqual(recursive, map[string]bool{"D": true, "E": true, "F": true}) {
+		t.Errorf("unexpected recursive types: %v", recursive)
+	}
+}